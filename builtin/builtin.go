@@ -0,0 +1,50 @@
+// Package builtin lets Go code outside DAP add built-in functions to it.
+// A package registers its built-ins from its init; the dap command picks them
+// up when it imports that package.
+package builtin
+
+import "dap/internal/common"
+
+type (
+	// Value is anything a DAP program works with.
+	Value   = common.Value
+	Context = common.Context
+
+	Number = common.Number
+	String = common.String
+	List   = common.List
+	Array  = common.Array
+	Null   = common.Null
+
+	// Param describes one parameter. A variadic parameter must be the last
+	// one and takes zero or more arguments.
+	Param = common.Param
+	// Func is the Go side of a built-in. args holds the arguments in call
+	// order, already checked against the parameters. A returned error becomes
+	// a runtime error at the call, and a nil value becomes null.
+	Func    = common.BuiltinFunc
+	Option  = common.BuiltinOption
+	Builtin = common.Builtin
+)
+
+// Register adds a built-in named name. It must run before the program
+// starts, e.g. from an init. Registering a name again replaces the earlier
+// built-in, including the ones that come with DAP.
+func Register(name string, params []Param, fn Func, opsi ...Option) *Builtin {
+	return common.RegisterBuiltin(name, params, fn, opsi...)
+}
+
+// Alias makes the built-in callable under other names as well.
+func Alias(names ...string) Option {
+	return common.Alias(names...)
+}
+
+// Doc attaches a one-line description, shown by `dap --help`.
+func Doc(doc string) Option {
+	return common.Doc(doc)
+}
+
+// Lookup finds a registered built-in by its name or one of its aliases.
+func Lookup(name string) (*Builtin, bool) {
+	return common.CariBuiltin(name)
+}
@@ -0,0 +1,96 @@
+package builtin_test
+
+import (
+	"dap/builtin"
+	"dap/internal/testutil"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func init() {
+	builtin.Register("double", []builtin.Param{{Name: "n"}},
+		func(ctx *builtin.Context, args []builtin.Value) (builtin.Value, error) {
+			n, ok := args[0].(builtin.Number)
+			if !ok {
+				return nil, fmt.Errorf("double expects a number")
+			}
+			return builtin.Number{Value: n.Value * 2}, nil
+		},
+		builtin.Alias("twice"), builtin.Doc("doubles a number"))
+}
+
+func TestRegisteredNameDoesNotTakeOverDAP(t *testing.T) {
+	// DAP's length runs the Go method ExecuteLength.
+	builtin.Register("Length", nil, func(ctx *builtin.Context, args []builtin.Value) (builtin.Value, error) {
+		return builtin.Number{Value: -1}, nil
+	})
+
+	tabel, errorNya := testutil.Jalankan(t, `program P
+dictionary
+  a, b : integer
+algorithm
+  a <- length("abc")
+  b <- Length()
+endprogram`)
+	if errorNya != nil {
+		t.Fatal(errorNya.As_string())
+	}
+
+	for nama, ingin := range map[string]float64{"a": 3, "b": -1} {
+		if got := tabel.Get(nama).(builtin.Number).Value; got != ingin {
+			t.Errorf("%s = %v, want %v", nama, got, ingin)
+		}
+	}
+	if _, ok := builtin.Lookup("length"); ok {
+		t.Errorf("Lookup(\"length\") found a registered built-in")
+	}
+}
+
+func TestRegisteredBuiltinIsCalled(t *testing.T) {
+	tabel, errorNya := testutil.Jalankan(t, `program P
+dictionary
+  a, b : integer
+algorithm
+  a <- double(21)
+  b <- twice(a)
+endprogram`)
+	if errorNya != nil {
+		t.Fatal(errorNya.As_string())
+	}
+
+	for nama, ingin := range map[string]float64{"a": 42, "b": 84} {
+		if got := tabel.Get(nama).(builtin.Number).Value; got != ingin {
+			t.Errorf("%s = %v, want %v", nama, got, ingin)
+		}
+	}
+	if b, ok := builtin.Lookup("twice"); !ok || b.Name != "double" || b.Doc != "doubles a number" {
+		t.Errorf("Lookup(\"twice\") = %v, %v", b, ok)
+	}
+}
+
+func TestRegisteredBuiltinChecksArguments(t *testing.T) {
+	tests := []struct {
+		call, ingin string
+	}{
+		{"double()", "1 too few args passed into 'double'"},
+		{"double(1, 2)", "1 too many args passed into 'double'"},
+		{`double("x")`, "double expects a number"},
+	}
+
+	for _, tt := range tests {
+		_, errorNya := testutil.Jalankan(t, fmt.Sprintf(`program P
+dictionary
+  a : integer
+algorithm
+  a <- %s
+endprogram`, tt.call))
+		if errorNya == nil {
+			t.Errorf("%s: no error, want %q", tt.call, tt.ingin)
+			continue
+		}
+		if !strings.Contains(errorNya.Details, tt.ingin) {
+			t.Errorf("%s: error %q, want %q", tt.call, errorNya.Details, tt.ingin)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package cli
+
+import (
+	"dap/internal/common"
+	"dap/internal/flowchart"
+	"dap/internal/lexer"
+	"dap/internal/parser"
+	"dap/internal/similar"
+	"dap/internal/translator"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ProgramDAP is a parsed DAP file, for the subcommands that work on the
+// syntax tree without running it.
+type ProgramDAP struct {
+	FileName string
+	Source   string
+	Name     string
+	Ast      common.Expr
+	Comments []lexer.Comment
+}
+
+func BacaAST(fileName string) (*ProgramDAP, error) {
+	bytes, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("Error: File '%s' not found or cannot be read.", fileName)
+	}
+
+	source := string(bytes)
+	tokens, err := lexer.Tokenize(source, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := lexer.Comments(source, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	ProgramName := "<program>"
+	Ast := parser.CreateParser(tokens, false).Parse(&ProgramName).(*common.ParseResult)
+	if Ast.Error != nil {
+		return nil, fmt.Errorf("%s", Ast.Error.As_string())
+	}
+
+	return &ProgramDAP{
+		FileName: fileName,
+		Source:   source,
+		Name:     ProgramName,
+		Ast:      Ast.Node,
+		Comments: comments,
+	}, nil
+}
+
+// ambilOpsi splits "--name=value" style options from the positional arguments.
+func ambilOpsi(args []string) (map[string]string, []string) {
+	opsi := map[string]string{}
+	posisi := make([]string, 0)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			posisi = append(posisi, arg)
+			continue
+		}
+
+		nama, nilai, adaNilai := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !adaNilai && nama == "o" && i+1 < len(args) {
+			nilai = args[i+1]
+			i++
+		}
+		opsi[nama] = nilai
+	}
+
+	return opsi, posisi
+}
+
+func keluarError(err any) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
+// PerintahBuild implements `dap build --emit=go|exe file.dap [-o output]`.
+func PerintahBuild(args []string) {
+	opsi, posisi := ambilOpsi(args)
+	if len(posisi) != 1 {
+		keluarError("Usage: dap build [--emit=go|exe] file.dap [-o output]")
+	}
+
+	emit := opsi["emit"]
+	if emit == "" {
+		emit = "go"
+	}
+
+	program, err := BacaAST(posisi[0])
+	if err != nil {
+		keluarError(err)
+	}
+
+	source, errTranslate := translator.ToGo(program.Ast, program.Name, program.Comments)
+	if errTranslate != nil {
+		keluarError(errTranslate.As_string())
+	}
+
+	switch emit {
+	case "go":
+		if opsi["o"] == "" {
+			fmt.Print(source)
+			return
+		}
+
+		if err := os.WriteFile(opsi["o"], []byte(source), 0644); err != nil {
+			keluarError(err)
+		}
+	case "exe":
+		output := opsi["o"]
+		if output == "" {
+			output = strings.TrimSuffix(filepath.Base(posisi[0]), filepath.Ext(posisi[0]))
+		}
+		output, _ = filepath.Abs(output)
+
+		dir, err := os.MkdirTemp("", "dap-build-")
+		if err != nil {
+			keluarError(err)
+		}
+		defer os.RemoveAll(dir)
+
+		os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module "+strings.ToLower(program.Name)+"\n\ngo 1.22\n"), 0644)
+		os.WriteFile(filepath.Join(dir, "main.go"), []byte(source), 0644)
+
+		cmd := exec.Command("go", "build", "-o", output, ".")
+		cmd.Dir = dir
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			os.RemoveAll(dir)
+			keluarError(fmt.Sprintf("go build failed: %v", err))
+		}
+	default:
+		keluarError(fmt.Sprintf("Unknown --emit target '%s' (expected go or exe)", emit))
+	}
+}
+
+// PerintahTranslate implements `dap translate --to=python|c file.dap [-o output]`.
+func PerintahTranslate(args []string) {
+	opsi, posisi := ambilOpsi(args)
+	if len(posisi) != 1 {
+		keluarError("Usage: dap translate --to=python|c|go file.dap [-o output]")
+	}
+
+	program, err := BacaAST(posisi[0])
+	if err != nil {
+		keluarError(err)
+	}
+
+	var source string
+	var errTranslate *common.Error
+	switch opsi["to"] {
+	case "python", "py":
+		source, errTranslate = translator.ToPython(program.Ast, program.Name, program.Comments)
+	case "c":
+		source, errTranslate = translator.ToC(program.Ast, program.Name, program.Comments)
+	case "go":
+		source, errTranslate = translator.ToGo(program.Ast, program.Name, program.Comments)
+	default:
+		keluarError(fmt.Sprintf("Unknown --to target '%s' (expected python, c or go)", opsi["to"]))
+	}
+
+	if errTranslate != nil {
+		keluarError(errTranslate.As_string())
+	}
+
+	if opsi["o"] == "" {
+		fmt.Print(source)
+		return
+	}
+
+	if err := os.WriteFile(opsi["o"], []byte(source), 0644); err != nil {
+		keluarError(err)
+	}
+}
+
+// PerintahFlowchart implements `dap flowchart [--format=dot|mermaid] [--function=name] file.dap [-o output]`.
+func PerintahFlowchart(args []string) {
+	opsi, posisi := ambilOpsi(args)
+	if len(posisi) != 1 {
+		keluarError("Usage: dap flowchart [--format=dot|mermaid] [--function=name] file.dap [-o output]")
+	}
+
+	program, err := BacaAST(posisi[0])
+	if err != nil {
+		keluarError(err)
+	}
+
+	chart := flowchart.FromProgram(program.Ast, program.Name)
+	if opsi["function"] != "" {
+		chart = flowchart.FromFunction(program.Ast, opsi["function"])
+		if chart == nil {
+			keluarError(fmt.Sprintf("Function '%s' is not defined in %s", opsi["function"], program.FileName))
+		}
+	}
+
+	var hasil string
+	switch opsi["format"] {
+	case "", "dot":
+		hasil = chart.Dot()
+	case "mermaid":
+		hasil = chart.Mermaid()
+	default:
+		keluarError(fmt.Sprintf("Unknown --format '%s' (expected dot or mermaid)", opsi["format"]))
+	}
+
+	if opsi["o"] == "" {
+		fmt.Print(hasil)
+		return
+	}
+
+	if err := os.WriteFile(opsi["o"], []byte(hasil), 0644); err != nil {
+		keluarError(err)
+	}
+}
+
+// JSONSchemaVersion is bumped whenever the JSON emitted by `dap parse --json`,
+// --show-token=json or --show-ast=json changes incompatibly (see docs/json.md).
+const JSONSchemaVersion = 1
+
+func cetakJSON(data any) {
+	bytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		keluarError(err)
+	}
+	fmt.Println(string(bytes))
+}
+
+// PerintahParse implements `dap parse [--json] file.dap`.
+func PerintahParse(args []string) {
+	opsi, posisi := ambilOpsi(args)
+	if len(posisi) != 1 {
+		keluarError("Usage: dap parse [--json] file.dap")
+	}
+	_, apakahJSON := opsi["json"]
+
+	bytes, err := os.ReadFile(posisi[0])
+	if err != nil {
+		keluarError(fmt.Sprintf("Error: File '%s' not found or cannot be read.", posisi[0]))
+	}
+
+	hasil := map[string]any{
+		"schemaVersion": JSONSchemaVersion,
+		"file":          posisi[0],
+	}
+
+	tokens, err := lexer.Tokenize(string(bytes), posisi[0])
+	if err != nil {
+		if !apakahJSON {
+			keluarError(err)
+		}
+		hasil["error"] = map[string]any{"name": "Lexer Error", "details": err.Error()}
+		cetakJSON(hasil)
+		os.Exit(1)
+	}
+
+	ProgramName := "<program>"
+	Ast := parser.CreateParser(tokens, false).Parse(&ProgramName).(*common.ParseResult)
+	if Ast.Error != nil {
+		if !apakahJSON {
+			keluarError(Ast.Error.As_string())
+		}
+		hasil["error"] = common.ErrorJSON(Ast.Error)
+		cetakJSON(hasil)
+		os.Exit(1)
+	}
+
+	if !apakahJSON {
+		common.PrintTreeAST(Ast.Node, "", true)
+		return
+	}
+
+	hasil["program"] = ProgramName
+	hasil["tokens"] = lexer.TokensJSON(tokens)
+	hasil["ast"] = common.ASTToJSON(Ast.Node)
+	cetakJSON(hasil)
+}
+
+// PerintahSimilar implements `dap similar [--format=csv|json] [--min-match=8]
+// [--threshold=0] dir-or-files... [-o output]`.
+func PerintahSimilar(args []string) {
+	opsi, posisi := ambilOpsi(args)
+	if len(posisi) == 0 {
+		keluarError("Usage: dap similar [--format=csv|json] [--min-match=8] [--threshold=0] directory|file.dap ... [-o output]")
+	}
+
+	minMatch := 8
+	if opsi["min-match"] != "" {
+		n, err := strconv.Atoi(opsi["min-match"])
+		if err != nil || n < 1 {
+			keluarError(fmt.Sprintf("--min-match must be a positive integer, got '%s'", opsi["min-match"]))
+		}
+		minMatch = n
+	}
+
+	threshold := 0.0
+	if opsi["threshold"] != "" {
+		n, err := strconv.ParseFloat(opsi["threshold"], 64)
+		if err != nil {
+			keluarError(fmt.Sprintf("--threshold must be a number between 0 and 1, got '%s'", opsi["threshold"]))
+		}
+		threshold = n
+	}
+
+	files := make([]string, 0)
+	for _, path := range posisi {
+		filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				keluarError(err)
+			}
+			if !d.IsDir() && (file == path || filepath.Ext(file) == ".dap") {
+				files = append(files, file)
+			}
+			return nil
+		})
+	}
+
+	programs := make([]*similar.Program, 0, len(files))
+	for _, file := range files {
+		program, err := BacaAST(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping %s:\n%v\n", file, err)
+			continue
+		}
+		programs = append(programs, similar.NewProgram(file, similar.Normalise(program.Ast), minMatch))
+	}
+
+	pasangan := make([]similar.Hasil, 0)
+	for _, v := range similar.SemuaPasangan(programs, minMatch) {
+		if v.Score >= threshold {
+			pasangan = append(pasangan, v)
+		}
+	}
+
+	var hasil strings.Builder
+	switch opsi["format"] {
+	case "", "csv":
+		writer := csv.NewWriter(&hasil)
+		writer.Write([]string{"rank", "file_a", "file_b", "score", "matches"})
+		for i, v := range pasangan {
+			matches := make([]string, 0, len(v.Matches))
+			for _, m := range v.Matches {
+				matches = append(matches, fmt.Sprintf("%d-%d:%d-%d", m.A.Start, m.A.End, m.B.Start, m.B.End))
+			}
+			writer.Write([]string{strconv.Itoa(i + 1), v.A, v.B, strconv.FormatFloat(v.Score, 'f', 3, 64), strings.Join(matches, " ")})
+		}
+		writer.Flush()
+	case "json":
+		bytes, _ := json.MarshalIndent(pasangan, "", "  ")
+		hasil.Write(bytes)
+		hasil.WriteString("\n")
+	default:
+		keluarError(fmt.Sprintf("Unknown --format '%s' (expected csv or json)", opsi["format"]))
+	}
+
+	if opsi["o"] == "" {
+		fmt.Print(hasil.String())
+		return
+	}
+
+	if err := os.WriteFile(opsi["o"], []byte(hasil.String()), 0644); err != nil {
+		keluarError(err)
+	}
+}
@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"dap/internal/common"
+	"dap/internal/interpreter"
+	"dap/internal/lexer"
+	"dap/internal/parser"
+	"fmt"
+	"os"
+	"strings"
+)
+
+/*
+program GiveMeArray
+
+dictionary
+    i, n, total : integer
+algorithm
+    total <- 1
+    input i, n
+
+    while ((n != -99999) and (total < i)) do
+        total <- total + 1
+        input n
+    endwhile
+
+    if ((total <= i) and (n != -99999)) then
+        output n
+    else
+        output "EMPTY"
+    endif
+endprogram
+*/
+
+var TunjuinToken = false
+var TunjuinAST = false
+var TokenSebagaiJSON = false
+var ASTSebagaiJSON = false
+var FormatTrace = ""
+var ForKetat = false
+var globalSymbolTable = &common.SymbolTable{
+	Symbols: make(map[string]common.Value),
+}
+
+func JalaninProgram(source string, fileName string, ApakahSatuBaris bool) {
+	tokens, err := lexer.Tokenize(source, fileName)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if TunjuinToken && TokenSebagaiJSON {
+		cetakJSON(lexer.TokensJSON(tokens))
+	} else if TunjuinToken {
+		fmt.Println("########   TOKEN   #########")
+		for _, token := range tokens {
+			token.Debug()
+		}
+	}
+
+	ProgramName := "<program>"
+	Parser := parser.CreateParser(tokens, ApakahSatuBaris)
+	Ast := Parser.Parse(&ProgramName).(*common.ParseResult)
+
+	if TunjuinAST && !ASTSebagaiJSON {
+		fmt.Println("########   AST   #########")
+	}
+
+	if Ast.Error != nil {
+		fmt.Println(Ast.Error.As_string())
+	} else {
+		if TunjuinAST && ASTSebagaiJSON {
+			cetakJSON(common.ASTToJSON(Ast.Node))
+		} else if TunjuinAST {
+			common.PrintTreeAST(Ast.Node, "", true)
+		}
+
+		inter := interpreter.Interpreter{ForKetat: ForKetat}
+		if FormatTrace != "" {
+			inter.Trace = interpreter.NewTrace(Ast.Node, globalSymbolTable)
+		}
+		context := &common.Context{
+			DisplayName: ProgramName,
+		}
+
+		context.Symbol_Table = globalSymbolTable
+
+		if (TunjuinAST && !ASTSebagaiJSON) || (TunjuinToken && !TokenSebagaiJSON) {
+			fmt.Println("########   RESULT   #########")
+		}
+
+		hasil := inter.Visit(Ast.Node, context).(*common.RTResult)
+		common.TutupBaris()
+		common.SimpanBerkas()
+		if hasil.Error != nil {
+			fmt.Println(hasil.Error.As_string())
+		}
+
+		if inter.Trace != nil {
+			fmt.Println("########   TRACE   #########")
+			switch FormatTrace {
+			case "csv":
+				fmt.Print(inter.Trace.CSV())
+			case "markdown", "md":
+				fmt.Print(inter.Trace.Markdown())
+			default:
+				fmt.Print(inter.Trace.Text())
+			}
+		}
+	}
+}
+
+// Main runs the dap command with the arguments in os.Args. A program of your
+// own can call it after importing the packages that register its built-ins.
+func Main() {
+	common.IsiGlobal(globalSymbolTable)
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "build":
+			PerintahBuild(os.Args[2:])
+			return
+		case "translate":
+			PerintahTranslate(os.Args[2:])
+			return
+		case "flowchart":
+			PerintahFlowchart(os.Args[2:])
+			return
+		case "parse":
+			PerintahParse(os.Args[2:])
+			return
+		case "similar":
+			PerintahSimilar(os.Args[2:])
+			return
+		}
+	}
+
+	fileName := ""
+	for i, command := range os.Args {
+		if i == 1 && (len(command) < 2 || command[:2] != "--") {
+			fileName = command
+		}
+
+		if command == "--show-token" || command == "--show-token=json" {
+			TunjuinToken = true
+			TokenSebagaiJSON = command == "--show-token=json"
+		}
+
+		if command == "--show-ast" || command == "--show-ast=json" {
+			TunjuinAST = true
+			ASTSebagaiJSON = command == "--show-ast=json"
+		}
+
+		if command == "--trace" {
+			FormatTrace = "text"
+		} else if strings.HasPrefix(command, "--trace=") {
+			FormatTrace = strings.TrimPrefix(command, "--trace=")
+		}
+
+		if command == "--strict-for" {
+			ForKetat = true
+		}
+
+		if strings.HasPrefix(command, "--files=") {
+			common.FolderBerkas = strings.TrimPrefix(command, "--files=")
+		}
+
+		if command == "--help" || command == "-h" {
+			fmt.Println("DAP, A friendly Pseudocode for you to learn basic logic")
+			fmt.Println("Usage:")
+			fmt.Println("  dap [file.dap]    Run a DAP program file")
+			fmt.Println("  dap               Enter interactive console mode")
+			fmt.Println("  dap build [--emit=go|exe] file.dap [-o output]")
+			fmt.Println("                    Translate a program to Go source or a native binary")
+			fmt.Println("  dap translate --to=python|c file.dap [-o output]")
+			fmt.Println("                    Translate a program to Python or C for comparison")
+			fmt.Println("  dap flowchart [--format=dot|mermaid] [--function=name] file.dap [-o output]")
+			fmt.Println("                    Draw the flowchart of a program or one of its functions")
+			fmt.Println("  dap parse [--json] file.dap")
+			fmt.Println("                    Print the tokens and syntax tree without running the program")
+			fmt.Println("  dap similar [--format=csv|json] [--min-match=8] [--threshold=0] dir|file.dap ...")
+			fmt.Println("                    Rank pairs of submissions by structural similarity")
+			fmt.Println("")
+			fmt.Println("Options:")
+			fmt.Println("  --show-token[=json]")
+			fmt.Println("                    Show tokens during execution")
+			fmt.Println("  --show-ast[=json] Show abstract syntax tree during execution")
+			fmt.Println("  --trace[=text|csv|markdown]")
+			fmt.Println("                    Print a trace table of the dictionary variables after the run")
+			fmt.Println("  --files=DIR       Directory the program's files are in (default: the current one)")
+			fmt.Println("  --strict-for      Make the variable of a for loop read-only inside the loop")
+			fmt.Println("  --help, -h        Show this help message")
+			if registered := common.SemuaBuiltin(); len(registered) > 0 {
+				fmt.Println("")
+				fmt.Println("Registered built-ins:")
+				for _, b := range registered {
+					if len(b.Signature()) > 16 {
+						fmt.Printf("  %s\n", b.Signature())
+						fmt.Printf("  %-18s%s\n", "", b.Doc)
+					} else {
+						fmt.Printf("  %-18s%s\n", b.Signature(), b.Doc)
+					}
+					if len(b.Aliases) > 0 {
+						fmt.Printf("  %-18salso %s\n", "", strings.Join(b.Aliases, ", "))
+					}
+				}
+			}
+			os.Exit(0)
+		}
+	}
+
+	if fileName == "" {
+		fmt.Println("Welcome to DAP. Friendly Pseudocode.")
+		fmt.Println("Type `help` for information.")
+
+		for {
+			fmt.Print(">>> ")
+			// The same reader as read, so input typed for a program isn't lost.
+			line, err := common.Masukan.ReadString('\n')
+			text := strings.TrimRight(line, "\r\n")
+			if err != nil && text == "" {
+				fmt.Println()
+				os.Exit(0)
+			}
+
+			if text == "" {
+				continue
+			}
+
+			if text == "exit" || text == "exit()" {
+				fmt.Println("Jumpe lagi!")
+				os.Exit(0)
+			}
+
+			if text == "help" {
+				fmt.Println("DAP, A friendly Pseudocode for you to learn basic logic")
+				fmt.Println("To run the command `dap [NameOfAFile.dap]` Without the namefile would access to console")
+				fmt.Println("Extra command")
+				fmt.Println(" --show-token | Showing the Token")
+				fmt.Println(" --show-AST | Showing the AST")
+				continue
+			}
+
+			JalaninProgram(text, "<program>", true)
+		}
+	}
+
+	bytes, err := os.ReadFile(fileName)
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: File '%s' not found or cannot be read.\n", fileName)
+		os.Exit(1)
+	}
+
+	source := string(bytes)
+	JalaninProgram(source, fileName, false)
+}
@@ -0,0 +1,219 @@
+package common
+
+import (
+	"dap/internal/lexer"
+	"reflect"
+)
+
+// ASTToJSON converts a syntax tree into plain maps and slices for
+// encoding/json. The schema is documented in docs/json.md; keep both in sync.
+func ASTToJSON(node Expr) any {
+	if node == nil {
+		return nil
+	}
+
+	if pr, ok := node.(*ParseResult); ok {
+		return ASTToJSON(pr.Node)
+	}
+
+	hasil := map[string]any{
+		"kind":  reflect.TypeOf(node).Name(),
+		"start": lexer.PositionJSON(node.GetPosStart()),
+		"end":   lexer.PositionJSON(node.GetPosEnd()),
+	}
+
+	switch n := node.(type) {
+	case NumberNode:
+		hasil["value"] = n.Token.Value
+	case StringNode:
+		hasil["value"] = n.Token.Value[1 : len(n.Token.Value)-1]
+	case ListNode:
+		hasil["elements"] = listJSON(n.ElementNode)
+	case BinOpNode:
+		hasil["operator"] = n.Operator.Value
+		hasil["left"] = ASTToJSON(n.Left)
+		hasil["right"] = ASTToJSON(n.Right)
+	case UnaryOpNode:
+		hasil["operator"] = n.Operator.Value
+		hasil["operand"] = ASTToJSON(n.Node)
+	case VarAssignNode:
+		hasil["name"] = n.VarName.Value
+		hasil["value"] = ASTToJSON(n.ValueNode)
+		hasil["const"] = n.ApakahConst
+	case VarAccessNode:
+		hasil["name"] = n.VarNameTok.Value
+	case IfNode:
+		cases := make([]any, 0, len(n.Cases))
+		for _, kasus := range n.Cases {
+			cases = append(cases, map[string]any{
+				"condition": ASTToJSON(kasus.Kondisi),
+				"body":      ASTToJSON(kasus.Isi),
+			})
+		}
+		hasil["cases"] = cases
+		hasil["else"] = nil
+		if n.Else_case != nil && n.Else_case.Isi != nil {
+			hasil["else"] = ASTToJSON(n.Else_case.Isi)
+		}
+	case CaseNode:
+		hasil["subject"] = ASTToJSON(n.SubjectNode)
+		branches := make([]any, 0, len(n.Branches))
+		for _, cabang := range n.Branches {
+			labels := make([]any, 0, len(cabang.Labels))
+			for _, label := range cabang.Labels {
+				var high any
+				if label.High != nil {
+					high = ASTToJSON(label.High)
+				}
+				labels = append(labels, map[string]any{
+					"low":  ASTToJSON(label.Low),
+					"high": high,
+				})
+			}
+			branches = append(branches, map[string]any{
+				"labels": labels,
+				"body":   ASTToJSON(cabang.Isi),
+			})
+		}
+		hasil["branches"] = branches
+		hasil["otherwise"] = nil
+		if n.Otherwise != nil {
+			hasil["otherwise"] = ASTToJSON(n.Otherwise)
+		}
+	case ForNode:
+		hasil["variable"] = n.VarNameTok.Value
+		hasil["from"] = ASTToJSON(n.StartValueNode)
+		hasil["to"] = ASTToJSON(n.EndValueNode)
+		hasil["step"] = nil
+		if _, ok := n.StepValueNode.(NullNode); !ok {
+			hasil["step"] = ASTToJSON(n.StepValueNode)
+		}
+		hasil["downto"] = n.Downto
+		hasil["body"] = ASTToJSON(n.BodyNode)
+	case ForEachNode:
+		hasil["index"] = nil
+		if n.IndexTok != nil {
+			hasil["index"] = n.IndexTok.Value
+		}
+		hasil["variable"] = n.VarNameTok.Value
+		hasil["in"] = ASTToJSON(n.IterableNode)
+		hasil["body"] = ASTToJSON(n.BodyNode)
+	case WhileNode:
+		hasil["condition"] = ASTToJSON(n.KondisiNode)
+		hasil["body"] = ASTToJSON(n.BodyNode)
+	case RepeatNode:
+		hasil["body"] = ASTToJSON(n.BodyNode)
+		hasil["until"] = ASTToJSON(n.KondisiNode)
+	case FuncNode:
+		hasil["name"] = nil
+		if n.VarNameTok != nil {
+			hasil["name"] = n.VarNameTok.Value
+		}
+		params := make([]string, 0, len(n.ArgNameToks))
+		for _, arg := range n.ArgNameToks {
+			params = append(params, arg.Value)
+		}
+		hasil["params"] = params
+		hasil["body"] = ASTToJSON(n.BodyNode)
+		hasil["autoReturn"] = n.ShouldAutoReturn
+	case CallNode:
+		hasil["callee"] = ASTToJSON(n.NodeToCall)
+		hasil["args"] = listJSON(n.ArgNodes)
+	case ReturnNode:
+		hasil["value"] = ASTToJSON(n.NodeToReturn)
+	case DictionaryNode:
+		hasil["declarations"] = listJSON(n.VariableDiBuat)
+	case ArrayTypeNode:
+		hasil["low"] = ASTToJSON(n.StartNode)
+		hasil["high"] = ASTToJSON(n.EndNode)
+		hasil["of"] = ASTToJSON(n.OfType)
+	case FileTypeNode:
+		if n.OfType != nil {
+			hasil["of"] = ASTToJSON(n.OfType)
+		}
+	case ListTypeNode:
+		hasil["of"] = ASTToJSON(n.OfType)
+	case EnumTypeNode:
+		hasil["name"] = n.TypeTok.Value
+		hasil["values"] = n.NamaNilai()
+	case SubrangeTypeNode:
+		hasil["low"] = ASTToJSON(n.Low)
+		hasil["high"] = ASTToJSON(n.High)
+	case PointerTypeNode:
+		hasil["of"] = ASTToJSON(n.OfType)
+	case DerefNode:
+		hasil["pointer"] = ASTToJSON(n.Pointer)
+	case DerefAssignNode:
+		hasil["target"] = ASTToJSON(n.Deref)
+		hasil["value"] = ASTToJSON(n.ValueNode)
+	case ArrayIndexNode:
+		hasil["array"] = ASTToJSON(n.Left)
+		hasil["index"] = ASTToJSON(n.Index)
+		hasil["dimension"] = n.Dimension
+	case SliceNode:
+		hasil["array"] = ASTToJSON(n.Left)
+		hasil["low"] = ASTToJSON(n.Low)
+		hasil["high"] = ASTToJSON(n.High)
+	case ArrayAssignNode:
+		hasil["target"] = ASTToJSON(n.ArrayAccess)
+		hasil["value"] = ASTToJSON(n.ValueNode)
+	case TypeAliasNode:
+		hasil["name"] = n.AliasName.Value
+		hasil["type"] = ASTToJSON(n.TargetType)
+	case StructTypeNode:
+		hasil["name"] = n.StructName.Value
+		fields := make([]any, 0, len(n.Fields))
+		for _, field := range n.Fields {
+			fields = append(fields, map[string]any{
+				"name": field.VarName.Value,
+				"type": ASTToJSON(field.ValueNode),
+			})
+		}
+		hasil["fields"] = fields
+	case StructLiteralNode:
+		hasil["type"] = n.TypeTok.Value
+		if n.Positional {
+			hasil["values"] = listJSON(n.Values)
+		} else {
+			fields := make([]any, 0, len(n.Fields))
+			for _, field := range n.Fields {
+				fields = append(fields, map[string]any{
+					"name":  field.VarName.Value,
+					"value": ASTToJSON(field.ValueNode),
+				})
+			}
+			hasil["fields"] = fields
+		}
+	case MemberAccessNode:
+		hasil["object"] = ASTToJSON(n.Object)
+		hasil["member"] = n.MemberTok.Value
+	case MemberAssignNode:
+		hasil["target"] = ASTToJSON(n.MemberAccess)
+		hasil["value"] = ASTToJSON(n.ValueNode)
+	case FormatNode:
+		hasil["value"] = ASTToJSON(n.Value)
+		hasil["width"] = ASTToJSON(n.Width)
+		if n.Precision != nil {
+			hasil["precision"] = ASTToJSON(n.Precision)
+		}
+	}
+
+	return hasil
+}
+
+func listJSON(nodes []Expr) []any {
+	hasil := make([]any, 0, len(nodes))
+	for _, v := range nodes {
+		hasil = append(hasil, ASTToJSON(v))
+	}
+	return hasil
+}
+
+func ErrorJSON(err *Error) map[string]any {
+	return map[string]any{
+		"name":    err.ErrorName,
+		"details": err.Details,
+		"start":   lexer.PositionJSON(&err.PosStart),
+		"end":     lexer.PositionJSON(&err.PosEnd),
+	}
+}
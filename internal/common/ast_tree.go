@@ -2,6 +2,7 @@ package common
 
 import (
 	"fmt"
+	"strings"
 )
 
 func PrintTreeAST(node Expr, indent string, last bool) {
@@ -66,13 +67,38 @@ func PrintTreeAST(node Expr, indent string, last bool) {
 			children = append(children, n.Else_case.Isi)
 			childNames = append(childNames, "Else")
 		}
+	case CaseNode:
+		children = []Expr{n.SubjectNode}
+		childNames = []string{"Subject"}
+		for _, cabang := range n.Branches {
+			labels := make([]string, 0, len(cabang.Labels))
+			for _, label := range cabang.Labels {
+				labels = append(labels, label.Print())
+			}
+			children = append(children, cabang.Isi)
+			childNames = append(childNames, strings.Join(labels, ", "))
+		}
+		if n.Otherwise != nil {
+			children = append(children, n.Otherwise)
+			childNames = append(childNames, "Otherwise")
+		}
 	case WhileNode:
 		children = []Expr{n.KondisiNode, n.BodyNode}
 		childNames = []string{"Condition", "Body"}
 	case ForNode:
 		info = fmt.Sprintf(": %s", n.VarNameTok.Value)
+		if n.Downto {
+			info += " (downto)"
+		}
 		children = []Expr{n.StartValueNode, n.EndValueNode, n.StepValueNode, n.BodyNode}
 		childNames = []string{"Start", "End", "Step", "Body"}
+	case ForEachNode:
+		info = fmt.Sprintf(": %s", n.VarNameTok.Value)
+		if n.IndexTok != nil {
+			info = fmt.Sprintf(": %s, %s", n.IndexTok.Value, n.VarNameTok.Value)
+		}
+		children = []Expr{n.IterableNode, n.BodyNode}
+		childNames = []string{"In", "Body"}
 	case RepeatNode:
 		children = []Expr{n.BodyNode, n.KondisiNode}
 		childNames = []string{"Body", "Condition"}
@@ -107,6 +133,33 @@ func PrintTreeAST(node Expr, indent string, last bool) {
 	case ArrayTypeNode:
 		children = []Expr{n.StartNode, n.EndNode, n.OfType}
 		childNames = []string{"Start", "End", "Type"}
+	case FileTypeNode:
+		if n.OfType == nil {
+			info = ": text"
+		} else {
+			children = []Expr{n.OfType}
+			childNames = []string{"Type"}
+		}
+	case ListTypeNode:
+		children = []Expr{n.OfType}
+		childNames = []string{"Type"}
+	case EnumTypeNode:
+		info = fmt.Sprintf(": %s", strings.Join(n.NamaNilai(), ", "))
+	case SubrangeTypeNode:
+		children = []Expr{n.Low, n.High}
+		childNames = []string{"Low", "High"}
+	case PointerTypeNode:
+		children = []Expr{n.OfType}
+		childNames = []string{"Type"}
+	case DerefNode:
+		children = []Expr{n.Pointer}
+		childNames = []string{"Pointer"}
+	case DerefAssignNode:
+		children = []Expr{n.Deref, n.ValueNode}
+		childNames = []string{"Target", "Value"}
+	case SliceNode:
+		children = []Expr{n.Left, n.Low, n.High}
+		childNames = []string{"Array", "Low", "High"}
 	case MemberAccessNode:
 		info = fmt.Sprintf(": .%s", n.MemberTok.Value)
 		children = []Expr{n.Object}
@@ -114,6 +167,13 @@ func PrintTreeAST(node Expr, indent string, last bool) {
 	case MemberAssignNode:
 		children = []Expr{n.MemberAccess, n.ValueNode}
 		childNames = []string{"Target", "Value"}
+	case FormatNode:
+		children = []Expr{n.Value, n.Width}
+		childNames = []string{"Value", "Width"}
+		if n.Precision != nil {
+			children = append(children, n.Precision)
+			childNames = append(childNames, "Precision")
+		}
 	case TypeAliasNode:
 		info = fmt.Sprintf(": %s", n.AliasName.Value)
 		children = []Expr{n.TargetType}
@@ -124,6 +184,16 @@ func PrintTreeAST(node Expr, indent string, last bool) {
 			children = append(children, field)
 			childNames = append(childNames, fmt.Sprintf("Field[%d]", i))
 		}
+	case StructLiteralNode:
+		info = fmt.Sprintf(": %s", n.TypeTok.Value)
+		for i, v := range n.Values {
+			children = append(children, v)
+			childNames = append(childNames, fmt.Sprintf("Value[%d]", i))
+		}
+		for _, field := range n.Fields {
+			children = append(children, field.ValueNode)
+			childNames = append(childNames, field.VarName.Value)
+		}
 	case ReturnNode:
 		children = []Expr{n.NodeToReturn}
 		childNames = []string{"Value"}
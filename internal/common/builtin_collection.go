@@ -0,0 +1,223 @@
+package common
+
+import (
+	"dap/tools"
+	"fmt"
+	"math"
+)
+
+// Type introspection and in-place changes to lists.
+//
+// append, insert and remove change the variable, element or field passed as
+// their first argument, the way read does: the new value goes back through the
+// context of the returned value, under the name of the variable or the text of
+// the element or field, and VisitCallNode writes it into the caller's scope.
+
+// ambilVariabel returns the name under which the first argument of a built-in
+// that changes it is written back.
+func (n BuiltInFunction) ambilVariabel(ctx *Context, rawArgs []Expr, fungsi string) (string, *Error) {
+	if len(rawArgs) > 0 {
+		switch target := rawArgs[0].(type) {
+		case VarAccessNode:
+			return target.VarNameTok.Value, nil
+		case ArrayIndexNode, MemberAccessNode, DerefNode:
+			return target.Print(), nil
+		}
+	}
+
+	err := RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%s expects a variable, an element or a field as its first argument", fungsi), ctx)
+	return "", &err
+}
+
+// kembalikanVariabel wraps the new value of a changed variable in a context
+// that holds nothing else, so only that variable is written back.
+func (n BuiltInFunction) kembalikanVariabel(name string, value Value) *Context {
+	return &Context{
+		DisplayName: n.Name,
+		Symbol_Table: &SymbolTable{
+			Symbols: map[string]Value{name: value},
+		},
+	}
+}
+
+// ambilKoleksi reads a list argument as its elements. An array has its size
+// fixed by its bounds, so nothing can be added to it or taken out.
+func (n BuiltInFunction) ambilKoleksi(ctx *Context, name string, fungsi string) ([]Value, *Error) {
+	switch value := ctx.Symbol_Table.Get(name).(type) {
+	case List:
+		return value.Elements, nil
+	case Array:
+		err := RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%s can't change the size of an array[%d..%d], declare a list of T to add or remove elements", fungsi, value.Start, value.End), ctx)
+		return nil, &err
+	}
+
+	err := RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%s expects a list, got %s", fungsi, NamaTipe(ctx.Symbol_Table.Get(name))), ctx)
+	return nil, &err
+}
+
+// gantiKoleksi rebuilds a list around new elements taken from it. When the
+// list is shared its elements end up held by the new list as well, so they
+// are marked as shared; an owned list is dropped, so they aren't copied.
+func gantiKoleksi(value Value, elements []Value) Value {
+	list := value.(List)
+	if list.dibagi == nil || *list.dibagi {
+		for i, element := range elements {
+			elements[i] = element.Copy()
+		}
+	}
+
+	list.Elements = elements
+	return MilikSendiri(list)
+}
+
+// ambilPosisi reads a position argument and checks it against [awal..akhir].
+func (n BuiltInFunction) ambilPosisi(ctx *Context, name string, fungsi string, awal, akhir int) (int, *Error) {
+	value := ctx.Symbol_Table.Get(name)
+	number, ok := value.(Number)
+	if !ok || number.Value != math.Trunc(number.Value) {
+		err := RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%s expects an integer position, got %s", fungsi, PrintValueInterpreter(value)), ctx)
+		return 0, &err
+	}
+
+	posisi := int(number.Value)
+	if posisi < awal || posisi > akhir {
+		err := RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%s: position %d out of bounds [%d..%d]", fungsi, posisi, awal, akhir), ctx)
+		return 0, &err
+	}
+
+	return posisi, nil
+}
+
+func apakahBulat(value Value) bool {
+	number, ok := value.(Number)
+	return ok && number.Value == math.Trunc(number.Value)
+}
+
+// ExecuteIsInteger is true for a whole number. Values don't remember their
+// declared type, so a real variable holding 2.0 counts as an integer too.
+func (n BuiltInFunction) ExecuteIsInteger() ([]string, func(*Context, []Expr) Value) {
+	return []string{"value"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+		return res.Success(Number{Value: float64(tools.GetComparison(apakahBulat(ctx.Symbol_Table.Get("value"))))})
+	}
+}
+
+func (n BuiltInFunction) ExecuteIsReal() ([]string, func(*Context, []Expr) Value) {
+	return []string{"value"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+		_, apakahNumber := ctx.Symbol_Table.Get("value").(Number)
+		return res.Success(Number{Value: float64(tools.GetComparison(apakahNumber))})
+	}
+}
+
+func (n BuiltInFunction) ExecuteIsString() ([]string, func(*Context, []Expr) Value) {
+	return []string{"value"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+		_, apakahString := ctx.Symbol_Table.Get("value").(String)
+		return res.Success(Number{Value: float64(tools.GetComparison(apakahString))})
+	}
+}
+
+// ExecuteTypeOf names the type of a value, telling integers and reals apart
+// by whether the number is whole.
+func (n BuiltInFunction) ExecuteTypeOf() ([]string, func(*Context, []Expr) Value) {
+	return []string{"value"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		value := ctx.Symbol_Table.Get("value")
+		if _, ok := value.(Number); ok {
+			if apakahBulat(value) {
+				return res.Success(String{Value: "integer"})
+			}
+			return res.Success(String{Value: "real"})
+		}
+
+		return res.Success(String{Value: NamaTipe(value)})
+	}
+}
+
+// ExecuteAppend adds values to the end of a list variable.
+func (n BuiltInFunction) ExecuteAppend() ([]string, func(*Context, []Expr) Value) {
+	return []string{"list", "...values"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		name, err := n.ambilVariabel(ctx, rawArgs, "append")
+		if err != nil {
+			return res.Failure(*err)
+		}
+		if _, err := n.ambilKoleksi(ctx, "list", "append"); err != nil {
+			return res.Failure(*err)
+		}
+
+		// Appending to an owned list doesn't touch the elements a copy taken
+		// before can see, so it happens in place; only a shared one is cloned.
+		list := SiapDiubah(ctx.Symbol_Table.Get("list")).(List)
+		for _, value := range ctx.Symbol_Table.Get("values").(List).Elements {
+			list.Elements = append(list.Elements, value.Copy())
+		}
+
+		return res.Success(Null{Context: n.kembalikanVariabel(name, list)})
+	}
+}
+
+// ExecuteInsert puts a value at a position of a list variable,
+// shifting the elements from there on by one. Inserting just past the last
+// element appends.
+func (n BuiltInFunction) ExecuteInsert() ([]string, func(*Context, []Expr) Value) {
+	return []string{"list", "position", "value"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		name, err := n.ambilVariabel(ctx, rawArgs, "insert")
+		if err != nil {
+			return res.Failure(*err)
+		}
+		elements, err := n.ambilKoleksi(ctx, "list", "insert")
+		if err != nil {
+			return res.Failure(*err)
+		}
+		posisi, err := n.ambilPosisi(ctx, "position", "insert", 1, len(elements)+1)
+		if err != nil {
+			return res.Failure(*err)
+		}
+
+		i := posisi - 1
+		hasil := make([]Value, 0, len(elements)+1)
+		hasil = append(hasil, elements[:i]...)
+		hasil = append(hasil, ctx.Symbol_Table.Get("value").Copy())
+		hasil = append(hasil, elements[i:]...)
+
+		return res.Success(Null{Context: n.kembalikanVariabel(name, gantiKoleksi(ctx.Symbol_Table.Get("list"), hasil))})
+	}
+}
+
+// ExecuteRemove takes the element at a position out of a list variable and
+// returns it.
+func (n BuiltInFunction) ExecuteRemove() ([]string, func(*Context, []Expr) Value) {
+	return []string{"list", "position"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		name, err := n.ambilVariabel(ctx, rawArgs, "remove")
+		if err != nil {
+			return res.Failure(*err)
+		}
+		elements, err := n.ambilKoleksi(ctx, "list", "remove")
+		if err != nil {
+			return res.Failure(*err)
+		}
+		if len(elements) == 0 {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, "remove: the list is empty", ctx))
+		}
+		posisi, err := n.ambilPosisi(ctx, "position", "remove", 1, len(elements))
+		if err != nil {
+			return res.Failure(*err)
+		}
+
+		i := posisi - 1
+		hasil := make([]Value, 0, len(elements)-1)
+		hasil = append(hasil, elements[:i]...)
+		hasil = append(hasil, elements[i+1:]...)
+
+		removed := elements[i].Copy().Set_context(n.kembalikanVariabel(name, gantiKoleksi(ctx.Symbol_Table.Get("list"), hasil)))
+		return res.Success(removed)
+	}
+}
@@ -0,0 +1,201 @@
+package common
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+var acak = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// SeedRandom makes random() repeat the same sequence, for reproducible runs.
+func SeedRandom(seed int64) {
+	acak = rand.New(rand.NewSource(seed))
+}
+
+// ambilAngka reads a numeric argument of a built-in, failing with the call
+// position when it's something else.
+func (n BuiltInFunction) ambilAngka(ctx *Context, name string, fungsi string) (float64, *Error) {
+	value := ctx.Symbol_Table.Get(name)
+	number, ok := value.(Number)
+	if !ok {
+		err := RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%s expects a number, got %s", fungsi, NamaTipe(value)), ctx)
+		return 0, &err
+	}
+	return number.Value, nil
+}
+
+// fungsiMatematika wraps a one-argument function of math as a built-in.
+func (n BuiltInFunction) fungsiMatematika(fungsi string, f func(float64) float64) ([]string, func(*Context, []Expr) Value) {
+	return []string{"value"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		x, err := n.ambilAngka(ctx, "value", fungsi)
+		if err != nil {
+			return res.Failure(*err)
+		}
+
+		hasil := f(x)
+		if math.IsNaN(hasil) || math.IsInf(hasil, 0) {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%s(%s) is undefined", fungsi, PrintValueInterpreter(Number{Value: x})), ctx))
+		}
+
+		return res.Success(Number{Value: hasil})
+	}
+}
+
+func (n BuiltInFunction) ExecuteAbs() ([]string, func(*Context, []Expr) Value) {
+	return n.fungsiMatematika("abs", math.Abs)
+}
+
+func (n BuiltInFunction) ExecuteSqrt() ([]string, func(*Context, []Expr) Value) {
+	return n.fungsiMatematika("sqrt", math.Sqrt)
+}
+
+func (n BuiltInFunction) ExecuteFloor() ([]string, func(*Context, []Expr) Value) {
+	return n.fungsiMatematika("floor", math.Floor)
+}
+
+func (n BuiltInFunction) ExecuteCeil() ([]string, func(*Context, []Expr) Value) {
+	return n.fungsiMatematika("ceil", math.Ceil)
+}
+
+func (n BuiltInFunction) ExecuteRound() ([]string, func(*Context, []Expr) Value) {
+	return n.fungsiMatematika("round", math.Round)
+}
+
+func (n BuiltInFunction) ExecuteTrunc() ([]string, func(*Context, []Expr) Value) {
+	return n.fungsiMatematika("trunc", math.Trunc)
+}
+
+func (n BuiltInFunction) ExecuteSin() ([]string, func(*Context, []Expr) Value) {
+	return n.fungsiMatematika("sin", math.Sin)
+}
+
+func (n BuiltInFunction) ExecuteCos() ([]string, func(*Context, []Expr) Value) {
+	return n.fungsiMatematika("cos", math.Cos)
+}
+
+func (n BuiltInFunction) ExecuteTan() ([]string, func(*Context, []Expr) Value) {
+	return n.fungsiMatematika("tan", math.Tan)
+}
+
+func (n BuiltInFunction) ExecuteLog() ([]string, func(*Context, []Expr) Value) {
+	return n.fungsiMatematika("log", math.Log)
+}
+
+func (n BuiltInFunction) ExecuteExp() ([]string, func(*Context, []Expr) Value) {
+	return n.fungsiMatematika("exp", math.Exp)
+}
+
+func (n BuiltInFunction) ExecutePow() ([]string, func(*Context, []Expr) Value) {
+	return []string{"base", "exponent"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		base, err := n.ambilAngka(ctx, "base", "pow")
+		if err != nil {
+			return res.Failure(*err)
+		}
+		exponent, err := n.ambilAngka(ctx, "exponent", "pow")
+		if err != nil {
+			return res.Failure(*err)
+		}
+
+		hasil := math.Pow(base, exponent)
+		if math.IsNaN(hasil) || math.IsInf(hasil, 0) {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("pow(%s, %s) is undefined", PrintValueInterpreter(Number{Value: base}), PrintValueInterpreter(Number{Value: exponent})), ctx))
+		}
+
+		return res.Success(Number{Value: hasil})
+	}
+}
+
+// terkecilAtauTerbesar implements min and max, over their arguments or over
+// the elements of a single array argument.
+func (n BuiltInFunction) terkecilAtauTerbesar(fungsi string, lebihBaik func(a, b float64) bool) ([]string, func(*Context, []Expr) Value) {
+	return []string{"...values"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		values := ctx.Symbol_Table.Get("values").(List).Elements
+		if len(values) == 1 {
+			switch v := values[0].(type) {
+			case Array:
+				values = v.Elements
+			case List:
+				values = v.Elements
+			}
+		}
+
+		if len(values) == 0 {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%s expects at least one number", fungsi), ctx))
+		}
+
+		var hasil float64
+		for i, v := range values {
+			number, ok := v.(Number)
+			if !ok {
+				return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%s expects numbers, got %s", fungsi, NamaTipe(v)), ctx))
+			}
+			if i == 0 || lebihBaik(number.Value, hasil) {
+				hasil = number.Value
+			}
+		}
+
+		return res.Success(Number{Value: hasil})
+	}
+}
+
+func (n BuiltInFunction) ExecuteMin() ([]string, func(*Context, []Expr) Value) {
+	return n.terkecilAtauTerbesar("min", func(a, b float64) bool { return a < b })
+}
+
+func (n BuiltInFunction) ExecuteMax() ([]string, func(*Context, []Expr) Value) {
+	return n.terkecilAtauTerbesar("max", func(a, b float64) bool { return a > b })
+}
+
+// ExecuteRandom gives a real in [0, 1) without arguments and an integer in
+// [0, n) with one, like Pascal's random.
+func (n BuiltInFunction) ExecuteRandom() ([]string, func(*Context, []Expr) Value) {
+	return []string{"...limit"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		limit := ctx.Symbol_Table.Get("limit").(List).Elements
+		switch len(limit) {
+		case 0:
+			return res.Success(Number{Value: acak.Float64()})
+		case 1:
+			number, ok := limit[0].(Number)
+			if !ok || number.Value < 1 || number.Value != math.Trunc(number.Value) {
+				return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("random expects a positive integer, got %s", PrintValueInterpreter(limit[0])), ctx))
+			}
+			return res.Success(Number{Value: float64(acak.Int63n(int64(number.Value)))})
+		}
+
+		return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%d too many args passed into 'random'", len(limit)-1), ctx))
+	}
+}
+
+// ExecuteRandomize reseeds random: from the clock without arguments, or from
+// the given seed so the same numbers come out on every run.
+func (n BuiltInFunction) ExecuteRandomize() ([]string, func(*Context, []Expr) Value) {
+	return []string{"...seed"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		seed := ctx.Symbol_Table.Get("seed").(List).Elements
+		switch len(seed) {
+		case 0:
+			SeedRandom(time.Now().UnixNano())
+			return res.Success(Null{})
+		case 1:
+			number, ok := seed[0].(Number)
+			if !ok {
+				return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("randomize expects a number, got %s", NamaTipe(seed[0])), ctx))
+			}
+			SeedRandom(int64(number.Value))
+			return res.Success(Null{})
+		}
+
+		return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%d too many args passed into 'randomize'", len(seed)-1), ctx))
+	}
+}
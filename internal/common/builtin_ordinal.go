@@ -0,0 +1,49 @@
+package common
+
+import (
+	"fmt"
+)
+
+func (n BuiltInFunction) ExecuteSucc() ([]string, func(*Context, []Expr) Value) {
+	return n.geser("succ", 1)
+}
+
+func (n BuiltInFunction) ExecutePred() ([]string, func(*Context, []Expr) Value) {
+	return n.geser("pred", -1)
+}
+
+// geser is succ and pred: the value langkah places after an ordinal value, a
+// whole number, a character or a value of an enumeration.
+func (n BuiltInFunction) geser(fungsi string, langkah int) ([]string, func(*Context, []Expr) Value) {
+	return []string{"value"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		value := ctx.Symbol_Table.Get("value")
+		ordinal, _, ok := NilaiOrdinal(value)
+		if !ok {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%s expects a whole number, a character or a value of an enumeration, got %s", fungsi, PrintValueInterpreter(value)), ctx))
+		}
+
+		switch value := value.(type) {
+		case Number:
+			return res.Success(Number{Value: float64(ordinal + langkah)})
+		case String:
+			if ordinal+langkah < 0 {
+				return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%s: there is no character before the one with code 0", fungsi), ctx))
+			}
+			return res.Success(String{Value: string(rune(ordinal + langkah))})
+		case Enum:
+			hasil, ada := value.Ke(ordinal + langkah)
+			if !ada {
+				ujung := "last"
+				if langkah < 0 {
+					ujung = "first"
+				}
+				return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%s: %s is the %s value of %s", fungsi, value.Print(), ujung, value.Tipe), ctx))
+			}
+			return res.Success(hasil.Set_context(ctx))
+		}
+
+		return res.Success(Null{})
+	}
+}
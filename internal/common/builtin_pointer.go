@@ -0,0 +1,29 @@
+package common
+
+import (
+	"fmt"
+)
+
+// ExecuteDispose frees what a pointer points to. new is run by the
+// interpreter, since it stores into its argument.
+func (n BuiltInFunction) ExecuteDispose() ([]string, func(*Context, []Expr) Value) {
+	return []string{"pointer"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		nama := "pointer"
+		if len(rawArgs) > 0 {
+			nama = rawArgs[0].Print()
+		}
+
+		value := ctx.Symbol_Table.Get("pointer")
+		pointer, ok := value.(Pointer)
+		if !ok {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("dispose expects a pointer, got %s", NamaTipe(value)), ctx))
+		}
+		if pesan := pointer.Buang(); pesan != "" {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("dispose: '%s' %s", nama, pesan), ctx))
+		}
+
+		return res.Success(Null{})
+	}
+}
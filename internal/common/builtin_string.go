@@ -0,0 +1,266 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ambilString reads a string argument of a built-in, failing with the call
+// position when it's something else.
+func (n BuiltInFunction) ambilString(ctx *Context, name string, fungsi string) (string, *Error) {
+	value := ctx.Symbol_Table.Get(name)
+	text, ok := value.(String)
+	if !ok {
+		err := RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%s expects a string, got %s", fungsi, NamaTipe(value)), ctx)
+		return "", &err
+	}
+	return text.Value, nil
+}
+
+// fungsiString wraps a string to string function as a one-argument built-in.
+func (n BuiltInFunction) fungsiString(fungsi string, f func(string) string) ([]string, func(*Context, []Expr) Value) {
+	return []string{"text"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		text, err := n.ambilString(ctx, "text", fungsi)
+		if err != nil {
+			return res.Failure(*err)
+		}
+
+		return res.Success(String{Value: f(text)})
+	}
+}
+
+func (n BuiltInFunction) ExecuteLength() ([]string, func(*Context, []Expr) Value) {
+	return []string{"value"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		switch value := ctx.Symbol_Table.Get("value").(type) {
+		case String:
+			return res.Success(Number{Value: float64(len([]rune(value.Value)))})
+		case Array:
+			return res.Success(Number{Value: float64(len(value.Elements))})
+		case List:
+			return res.Success(Number{Value: float64(len(value.Elements))})
+		}
+
+		return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, "length expects a string, an array or a list", ctx))
+	}
+}
+
+// ExecuteSubstring returns count characters of text starting at the 1-based
+// position start.
+func (n BuiltInFunction) ExecuteSubstring() ([]string, func(*Context, []Expr) Value) {
+	return []string{"text", "start", "count"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		text, apakahString := ctx.Symbol_Table.Get("text").(String)
+		start, apakahStart := ctx.Symbol_Table.Get("start").(Number)
+		count, apakahCount := ctx.Symbol_Table.Get("count").(Number)
+		if !apakahString || !apakahStart || !apakahCount {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, "substring expects (string, integer, integer)", ctx))
+		}
+
+		karakter := []rune(text.Value)
+		awal, panjang := int(start.Value), int(count.Value)
+		if awal < 1 || panjang < 0 || awal-1+panjang > len(karakter) {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("substring(%d, %d) is out of bounds for a string of length %d", awal, panjang, len(karakter)), ctx))
+		}
+
+		return res.Success(String{Value: string(karakter[awal-1 : awal-1+panjang])})
+	}
+}
+
+func (n BuiltInFunction) ExecuteUpper() ([]string, func(*Context, []Expr) Value) {
+	return n.fungsiString("upper", strings.ToUpper)
+}
+
+func (n BuiltInFunction) ExecuteLower() ([]string, func(*Context, []Expr) Value) {
+	return n.fungsiString("lower", strings.ToLower)
+}
+
+func (n BuiltInFunction) ExecuteTrim() ([]string, func(*Context, []Expr) Value) {
+	return n.fungsiString("trim", strings.TrimSpace)
+}
+
+func (n BuiltInFunction) ExecuteConcat() ([]string, func(*Context, []Expr) Value) {
+	return []string{"...texts"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		var hasil strings.Builder
+		for _, v := range ctx.Symbol_Table.Get("texts").(List).Elements {
+			text, ok := v.(String)
+			if !ok {
+				return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("concat expects strings, got %s", NamaTipe(v)), ctx))
+			}
+			hasil.WriteString(text.Value)
+		}
+
+		return res.Success(String{Value: hasil.String()})
+	}
+}
+
+// ExecuteFind gives the 1-based position of the first pattern in text, or 0
+// when it isn't there.
+func (n BuiltInFunction) ExecuteFind() ([]string, func(*Context, []Expr) Value) {
+	return []string{"text", "pattern"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		text, err := n.ambilString(ctx, "text", "find")
+		if err != nil {
+			return res.Failure(*err)
+		}
+		pattern, err := n.ambilString(ctx, "pattern", "find")
+		if err != nil {
+			return res.Failure(*err)
+		}
+
+		index := strings.Index(text, pattern)
+		if index < 0 {
+			return res.Success(Number{Value: 0})
+		}
+		return res.Success(Number{Value: float64(utf8.RuneCountInString(text[:index]) + 1)})
+	}
+}
+
+func (n BuiltInFunction) ExecuteReplace() ([]string, func(*Context, []Expr) Value) {
+	return []string{"text", "old", "new"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		text, err := n.ambilString(ctx, "text", "replace")
+		if err != nil {
+			return res.Failure(*err)
+		}
+		old, err := n.ambilString(ctx, "old", "replace")
+		if err != nil {
+			return res.Failure(*err)
+		}
+		new, err := n.ambilString(ctx, "new", "replace")
+		if err != nil {
+			return res.Failure(*err)
+		}
+
+		return res.Success(String{Value: strings.ReplaceAll(text, old, new)})
+	}
+}
+
+// ExecuteSplit cuts text at every separator into an array indexed from 1. An
+// empty separator splits it into characters.
+func (n BuiltInFunction) ExecuteSplit() ([]string, func(*Context, []Expr) Value) {
+	return []string{"text", "separator"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		text, err := n.ambilString(ctx, "text", "split")
+		if err != nil {
+			return res.Failure(*err)
+		}
+		separator, err := n.ambilString(ctx, "separator", "split")
+		if err != nil {
+			return res.Failure(*err)
+		}
+
+		bagian := strings.Split(text, separator)
+		elements := make([]Value, 0, len(bagian))
+		for _, v := range bagian {
+			elements = append(elements, String{Value: v})
+		}
+
+		return res.Success(Array{Elements: elements, Start: 1, End: len(elements)})
+	}
+}
+
+func (n BuiltInFunction) ExecuteOrd() ([]string, func(*Context, []Expr) Value) {
+	return []string{"character"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		if value, ok := ctx.Symbol_Table.Get("character").(Enum); ok {
+			return res.Success(Number{Value: float64(value.Ordinal)})
+		}
+
+		text, err := n.ambilString(ctx, "character", "ord")
+		if err != nil {
+			return res.Failure(*err)
+		}
+		if utf8.RuneCountInString(text) != 1 {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("ord expects a single character, got \"%s\"", text), ctx))
+		}
+
+		karakter, _ := utf8.DecodeRuneInString(text)
+		return res.Success(Number{Value: float64(karakter)})
+	}
+}
+
+func (n BuiltInFunction) ExecuteChr() ([]string, func(*Context, []Expr) Value) {
+	return []string{"code"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		code, err := n.ambilAngka(ctx, "code", "chr")
+		if err != nil {
+			return res.Failure(*err)
+		}
+		if code < 0 || code > utf8.MaxRune || code != float64(int64(code)) || !utf8.ValidRune(rune(code)) {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("chr expects a character code, got %s", PrintValueInterpreter(Number{Value: code})), ctx))
+		}
+
+		return res.Success(String{Value: string(rune(code))})
+	}
+}
+
+func (n BuiltInFunction) ExecuteToInt() ([]string, func(*Context, []Expr) Value) {
+	return []string{"value"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		switch value := ctx.Symbol_Table.Get("value").(type) {
+		case Number:
+			return res.Success(Number{Value: float64(int64(value.Value))})
+		case String:
+			hasil, err := strconv.ParseInt(strings.TrimSpace(value.Value), 10, 64)
+			if err != nil {
+				return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("toint cannot convert \"%s\" to an integer", value.Value), ctx))
+			}
+			return res.Success(Number{Value: float64(hasil)})
+		default:
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("toint expects a string or a number, got %s", NamaTipe(value)), ctx))
+		}
+	}
+}
+
+func (n BuiltInFunction) ExecuteToReal() ([]string, func(*Context, []Expr) Value) {
+	return []string{"value"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		switch value := ctx.Symbol_Table.Get("value").(type) {
+		case Number:
+			return res.Success(Number{Value: value.Value})
+		case String:
+			hasil, err := strconv.ParseFloat(strings.TrimSpace(value.Value), 64)
+			if err != nil {
+				return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("toreal cannot convert \"%s\" to a real", value.Value), ctx))
+			}
+			return res.Success(Number{Value: hasil})
+		default:
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("toreal expects a string or a number, got %s", NamaTipe(value)), ctx))
+		}
+	}
+}
+
+// ExecuteFormat fills a printf-like format string, see FormatTeks.
+func (n BuiltInFunction) ExecuteFormat() ([]string, func(*Context, []Expr) Value) {
+	return []string{"format", "...values"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		format, err := n.ambilString(ctx, "format", "format")
+		if err != nil {
+			return res.Failure(*err)
+		}
+
+		hasil, errorNya := FormatTeks(format, ctx.Symbol_Table.Get("values").(List).Elements)
+		if errorNya != nil {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, errorNya.Error(), ctx))
+		}
+
+		return res.Success(String{Value: hasil})
+	}
+}
@@ -0,0 +1,70 @@
+package common
+
+// Arrays, lists and structs are values: assigning one or passing it to a
+// function gives an independent copy. To keep that cheap the copy is made
+// lazily. Copy() only marks the storage as shared, and SiapDiubah clones it
+// the first time a shared value is written to.
+
+// MilikSendiri marks a freshly built array, list or struct as owned by
+// whoever holds it, so the first write to it doesn't clone anything.
+func MilikSendiri(value Value) Value {
+	milik := false
+	switch v := value.(type) {
+	case Array:
+		v.dibagi = &milik
+		return v
+	case List:
+		v.dibagi = &milik
+		return v
+	case Struct:
+		v.dibagi = &milik
+		return v
+	}
+	return value
+}
+
+// SiapDiubah returns a value whose elements or fields can be changed in
+// place without the change showing through any other copy of it.
+func SiapDiubah(value Value) Value {
+	switch v := value.(type) {
+	case Array:
+		if v.dibagi != nil && !*v.dibagi {
+			return v
+		}
+		v.Elements = salinElemen(v.Elements)
+		return MilikSendiri(v)
+	case List:
+		if v.dibagi != nil && !*v.dibagi {
+			return v
+		}
+		v.Elements = salinElemen(v.Elements)
+		return MilikSendiri(v)
+	case Struct:
+		if v.dibagi != nil && !*v.dibagi {
+			return v
+		}
+		fields := make(map[string]Value, len(v.Fields))
+		for nama, field := range v.Fields {
+			fields[nama] = field.Copy()
+		}
+		v.Fields = fields
+		return MilikSendiri(v)
+	}
+	return value
+}
+
+// salinElemen copies a slice of elements into a new backing array. The
+// elements themselves are only marked as shared.
+func salinElemen(elements []Value) []Value {
+	hasil := make([]Value, len(elements))
+	for i, element := range elements {
+		hasil[i] = element.Copy()
+	}
+	return hasil
+}
+
+func tandaiDibagi(dibagi *bool) {
+	if dibagi != nil {
+		*dibagi = true
+	}
+}
@@ -0,0 +1,109 @@
+package common
+
+import (
+	"dap/tools"
+	"fmt"
+	"math"
+)
+
+// Enum is a value of an enumerated type, e.g. Tue of
+// type Day : (Mon, Tue, Wed). Nama lists every value of the type in order and
+// Ordinal is the position of this one, counting from 0.
+type Enum struct {
+	Tipe      string
+	Nama      []string
+	Ordinal   int
+	Context   *Context
+	Pos_Start *tools.Position
+	Pos_End   *tools.Position
+}
+
+func (n Enum) Print() string {
+	return n.Nama[n.Ordinal]
+}
+
+func (n Enum) Set_pos(Pos_Start *tools.Position, Pos_End *tools.Position) Value {
+	n.Pos_Start = Pos_Start
+	n.Pos_End = Pos_End
+	return n
+}
+
+func (n Enum) Set_context(context *Context) Value {
+	n.Context = context
+	return n
+}
+
+func (n Enum) Get_context() *Context {
+	return n.Context
+}
+
+func (n Enum) Copy() Value {
+	return n
+}
+
+func (n Enum) Is_true() bool {
+	return true
+}
+
+// Ke is the value of the same type at ordinal, if there is one.
+func (n Enum) Ke(ordinal int) (Enum, bool) {
+	if ordinal < 0 || ordinal >= len(n.Nama) {
+		return n, false
+	}
+	n.Ordinal = ordinal
+	return n, true
+}
+
+// banding compares two values of the same enumerated type by their order.
+func (n Enum) banding(other Value, cocok func(a, b int) bool) (Value, *Error) {
+	if other, ok := other.(Enum); ok && other.Tipe == n.Tipe {
+		return Number{Value: float64(tools.GetComparison(cocok(n.Ordinal, other.Ordinal)))}.Set_context(n.Context), nil
+	}
+
+	err := RTError(safePos(n.Pos_Start), safePos(n.Pos_End), fmt.Sprintf("Illegal operation: cannot compare %s with %s", n.Tipe, NamaTipe(other)), n.Context)
+	return nil, &err
+}
+
+func (n Enum) Get_comparison_eq(other Value) (Value, *Error) {
+	return n.banding(other, func(a, b int) bool { return a == b })
+}
+
+func (n Enum) Get_comparison_nq(other Value) (Value, *Error) {
+	return n.banding(other, func(a, b int) bool { return a != b })
+}
+
+func (n Enum) Get_comparison_lt(other Value) (Value, *Error) {
+	return n.banding(other, func(a, b int) bool { return a < b })
+}
+
+func (n Enum) Get_comparison_lte(other Value) (Value, *Error) {
+	return n.banding(other, func(a, b int) bool { return a <= b })
+}
+
+func (n Enum) Get_comparison_gt(other Value) (Value, *Error) {
+	return n.banding(other, func(a, b int) bool { return a > b })
+}
+
+func (n Enum) Get_comparison_gte(other Value) (Value, *Error) {
+	return n.banding(other, func(a, b int) bool { return a >= b })
+}
+
+// NilaiOrdinal is the position of an ordinal value among the values of its
+// kind: a whole number is itself, a character is its code and an enumeration
+// value is its ordinal. jenis names the kind ("integer", "character" or the
+// enumeration) and ok is false for any other value.
+func NilaiOrdinal(v Value) (ordinal int, jenis string, ok bool) {
+	switch v := v.(type) {
+	case Number:
+		if v.Value == math.Trunc(v.Value) {
+			return int(v.Value), "integer", true
+		}
+	case String:
+		if huruf := []rune(v.Value); len(huruf) == 1 {
+			return int(huruf[0]), "character", true
+		}
+	case Enum:
+		return v.Ordinal, v.Tipe, true
+	}
+	return 0, "", false
+}
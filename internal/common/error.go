@@ -75,3 +75,12 @@ func RTError(PosStart tools.Position, PosEnd tools.Position, details string, con
 		Context:   context,
 	}
 }
+
+func TranslationError(PosStart tools.Position, PosEnd tools.Position, details string) Error {
+	return Error{
+		PosStart:  PosStart,
+		PosEnd:    PosEnd,
+		ErrorName: "Translation Error",
+		Details:   details,
+	}
+}
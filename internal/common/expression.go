@@ -4,6 +4,7 @@ import (
 	"dap/internal/lexer"
 	"dap/tools"
 	"fmt"
+	"strings"
 )
 
 func PrintValueAST(n Expr) string {
@@ -33,30 +34,92 @@ func PrintValueAST(n Expr) string {
 	case IfNode:
 		return fmt.Sprintf("IF %s THEN %s", PrintValueAST(n.Cases[0].Kondisi), PrintValueAST(n.Cases[0].Isi))
 	case ForNode:
+		arah := "TO"
+		if n.Downto {
+			arah = "DOWNTO"
+		}
 		switch n.StepValueNode.(type) {
 		case NullNode:
-			return fmt.Sprintf("FOR %s TO %s DO %s", PrintValueAST(n.StartValueNode), PrintValueAST(n.EndValueNode), PrintValueAST(n.BodyNode))
+			return fmt.Sprintf("FOR %s %s %s DO %s", PrintValueAST(n.StartValueNode), arah, PrintValueAST(n.EndValueNode), PrintValueAST(n.BodyNode))
 		}
 
-		return fmt.Sprintf("FOR %s TO %s STEP %s DO %s", PrintValueAST(n.StartValueNode), PrintValueAST(n.EndValueNode), PrintValueAST(n.StepValueNode), PrintValueAST(n.BodyNode))
+		return fmt.Sprintf("FOR %s %s %s STEP %s DO %s", PrintValueAST(n.StartValueNode), arah, PrintValueAST(n.EndValueNode), PrintValueAST(n.StepValueNode), PrintValueAST(n.BodyNode))
+	case ForEachNode:
+		if n.IndexTok != nil {
+			return fmt.Sprintf("FOREACH %s, %s IN %s DO %s", n.IndexTok.Value, n.VarNameTok.Value, PrintValueAST(n.IterableNode), PrintValueAST(n.BodyNode))
+		}
+		return fmt.Sprintf("FOREACH %s IN %s DO %s", n.VarNameTok.Value, PrintValueAST(n.IterableNode), PrintValueAST(n.BodyNode))
+	case CaseNode:
+		hasil := fmt.Sprintf("CASE %s OF", PrintValueAST(n.SubjectNode))
+		for _, branch := range n.Branches {
+			labels := make([]string, len(branch.Labels))
+			for i, label := range branch.Labels {
+				labels[i] = label.Print()
+			}
+			hasil += fmt.Sprintf(" %s: %s", strings.Join(labels, ", "), PrintValueAST(branch.Isi))
+		}
+		if n.Otherwise != nil {
+			hasil += fmt.Sprintf(" OTHERWISE: %s", PrintValueAST(n.Otherwise))
+		}
+		return hasil
 	case WhileNode:
 		return fmt.Sprintf("WHILE %s DO %s", PrintValueAST(n.KondisiNode), PrintValueAST(n.BodyNode))
 	case RepeatNode:
 		return fmt.Sprintf("REPEAT %s UNTIL %s", PrintValueAST(n.BodyNode), PrintValueAST(n.KondisiNode))
 	case ArrayTypeNode:
 		return fmt.Sprintf("ARRAY[%s..%s] OF %s", PrintValueAST(n.StartNode), PrintValueAST(n.EndNode), PrintValueAST(n.OfType))
+	case FileTypeNode:
+		if n.OfType == nil {
+			return "TEXT"
+		}
+		return fmt.Sprintf("FILE OF %s", PrintValueAST(n.OfType))
+	case ListTypeNode:
+		return fmt.Sprintf("LIST OF %s", PrintValueAST(n.OfType))
+	case EnumTypeNode:
+		return fmt.Sprintf("(%s)", strings.Join(n.NamaNilai(), ", "))
+	case SubrangeTypeNode:
+		return fmt.Sprintf("%s..%s", PrintValueAST(n.Low), PrintValueAST(n.High))
+	case PointerTypeNode:
+		return fmt.Sprintf("^%s", PrintValueAST(n.OfType))
+	case DerefNode:
+		return fmt.Sprintf("%s^", PrintValueAST(n.Pointer))
+	case DerefAssignNode:
+		return fmt.Sprintf("%s <- %s", PrintValueAST(n.Deref), PrintValueAST(n.ValueNode))
 	case ArrayIndexNode:
+		if n.Dimension > 1 {
+			return fmt.Sprintf("%s, %s]", strings.TrimSuffix(PrintValueAST(n.Left), "]"), PrintValueAST(n.Index))
+		}
 		return fmt.Sprintf("%s[%s]", PrintValueAST(n.Left), PrintValueAST(n.Index))
+	case SliceNode:
+		return fmt.Sprintf("%s[%s..%s]", PrintValueAST(n.Left), PrintValueAST(n.Low), PrintValueAST(n.High))
 	case ArrayAssignNode:
 		return fmt.Sprintf("%s <- %s", PrintValueAST(n.ArrayAccess), PrintValueAST(n.ValueNode))
 	case MemberAccessNode:
 		return fmt.Sprintf("%s.%s", PrintValueAST(n.Object), n.MemberTok.Value)
 	case MemberAssignNode:
 		return fmt.Sprintf("%s <- %s", PrintValueAST(n.MemberAccess), PrintValueAST(n.ValueNode))
+	case FormatNode:
+		if n.Precision != nil {
+			return fmt.Sprintf("%s:%s:%s", PrintValueAST(n.Value), PrintValueAST(n.Width), PrintValueAST(n.Precision))
+		}
+		return fmt.Sprintf("%s:%s", PrintValueAST(n.Value), PrintValueAST(n.Width))
 	case TypeAliasNode:
 		return fmt.Sprintf("TYPE %s: %s", n.AliasName.Value, PrintValueAST(n.TargetType))
 	case StructTypeNode:
 		return fmt.Sprintf("TYPE %s < STRUCT >", n.StructName.Value)
+	case StructLiteralNode:
+		if n.Positional {
+			nilai := make([]string, 0, len(n.Values))
+			for _, v := range n.Values {
+				nilai = append(nilai, PrintValueAST(v))
+			}
+			return fmt.Sprintf("%s(%s)", n.TypeTok.Value, strings.Join(nilai, ", "))
+		}
+		fields := make([]string, 0, len(n.Fields))
+		for _, field := range n.Fields {
+			fields = append(fields, fmt.Sprintf("%s: %s", field.VarName.Value, PrintValueAST(field.ValueNode)))
+		}
+		return fmt.Sprintf("%s<%s>", n.TypeTok.Value, strings.Join(fields, ", "))
 	case CallNode:
 		hasil := PrintValueAST(n.NodeToCall) + "("
 		for i, v := range n.ArgNodes {
@@ -99,6 +162,15 @@ type StructTypeNode struct {
 	Pos_End    *tools.Position
 }
 
+// NamaField lists the names of the fields in the order they are declared.
+func (n StructTypeNode) NamaField() []string {
+	hasil := make([]string, 0, len(n.Fields))
+	for _, field := range n.Fields {
+		hasil = append(hasil, field.VarName.Value)
+	}
+	return hasil
+}
+
 func (n StructTypeNode) expr()         {}
 func (n StructTypeNode) Print() string { return PrintValueAST(n) }
 func (n StructTypeNode) Name() string  { return "StructTypeNode" }
@@ -143,6 +215,26 @@ func (n MemberAssignNode) GetPosEnd() *tools.Position {
 	return n.Pos_End
 }
 
+// FormatNode is an argument of write with a field width and, for numbers, a
+// number of decimals: write(x:8:2). Precision is nil when left out.
+type FormatNode struct {
+	Value     Expr
+	Width     Expr
+	Precision Expr
+	Pos_Start *tools.Position
+	Pos_End   *tools.Position
+}
+
+func (n FormatNode) expr()         {}
+func (n FormatNode) Print() string { return PrintValueAST(n) }
+func (n FormatNode) Name() string  { return "FormatNode" }
+func (n FormatNode) GetPosStart() *tools.Position {
+	return n.Pos_Start
+}
+func (n FormatNode) GetPosEnd() *tools.Position {
+	return n.Pos_End
+}
+
 type NumberNode struct {
 	Token     lexer.Token
 	Pos_Start *tools.Position
@@ -183,8 +275,10 @@ func (n StringNode) GetPosEnd() *tools.Position {
 	return n.Pos_End
 }
 
+// ListNode is a list literal, or with Blok the statements of a block.
 type ListNode struct {
 	ElementNode []Expr
+	Blok        bool
 	Pos_Start   *tools.Position
 	Pos_End     *tools.Position
 }
@@ -347,20 +441,29 @@ func (n IfNode) Name() string {
 	return "IfNode"
 }
 func (n IfNode) GetPosStart() *tools.Position {
+	if n.Pos_Start != nil {
+		return n.Pos_Start
+	}
 	return n.Cases[0].Isi.GetPosStart()
 }
 func (n IfNode) GetPosEnd() *tools.Position {
-	if n.Else_case != nil {
+	if n.Pos_end != nil {
+		return n.Pos_end
+	}
+	if n.Else_case != nil && n.Else_case.Isi != nil {
 		return n.Else_case.Isi.GetPosEnd()
 	}
 	return n.Cases[len(n.Cases)-1].Isi.GetPosEnd()
 }
 
+// ForNode is `for i <- a to b step s do`. With Downto the loop is
+// `a downto b` and counts down, by s when a step is given.
 type ForNode struct {
 	VarNameTok       lexer.Token
 	StartValueNode   Expr
 	EndValueNode     Expr
 	StepValueNode    Expr
+	Downto           bool
 	BodyNode         Expr
 	ShouldReturnNull bool
 	Pos_Start        *tools.Position
@@ -381,6 +484,82 @@ func (n ForNode) GetPosEnd() *tools.Position {
 	return n.Pos_end
 }
 
+// ForEachNode is `foreach x in c do` or, with IndexTok, `foreach i, x in c do`.
+type ForEachNode struct {
+	IndexTok         *lexer.Token
+	VarNameTok       lexer.Token
+	IterableNode     Expr
+	BodyNode         Expr
+	ShouldReturnNull bool
+	Pos_Start        *tools.Position
+	Pos_end          *tools.Position
+}
+
+func (n ForEachNode) expr() {}
+func (n ForEachNode) Print() string {
+	return PrintValueAST(n)
+}
+func (n ForEachNode) Name() string {
+	return "ForEachNode"
+}
+func (n ForEachNode) GetPosStart() *tools.Position {
+	return n.Pos_Start
+}
+func (n ForEachNode) GetPosEnd() *tools.Position {
+	return n.Pos_end
+}
+
+// CaseLabel is one label of a case branch: a value, or a range when High is
+// set. Both ends are literals.
+type CaseLabel struct {
+	Low  Expr
+	High Expr
+}
+
+func (l CaseLabel) Print() string {
+	if l.High == nil {
+		return teksLiteral(l.Low)
+	}
+	return teksLiteral(l.Low) + ".." + teksLiteral(l.High)
+}
+
+// teksLiteral prints a label the way it was written, -5 rather than (-, 5).
+func teksLiteral(node Expr) string {
+	if unary, ok := node.(UnaryOpNode); ok {
+		return unary.Operator.Value + teksLiteral(unary.Node)
+	}
+	return PrintValueAST(node)
+}
+
+type CaseBranch struct {
+	Labels []CaseLabel
+	Isi    Expr
+}
+
+// CaseNode is `case x of ... endcase` and `depend on (x) ... enddepend`.
+// Otherwise is nil without an otherwise branch.
+type CaseNode struct {
+	SubjectNode Expr
+	Branches    []CaseBranch
+	Otherwise   Expr
+	Pos_Start   *tools.Position
+	Pos_End     *tools.Position
+}
+
+func (n CaseNode) expr() {}
+func (n CaseNode) Print() string {
+	return PrintValueAST(n)
+}
+func (n CaseNode) Name() string {
+	return "CaseNode"
+}
+func (n CaseNode) GetPosStart() *tools.Position {
+	return n.Pos_Start
+}
+func (n CaseNode) GetPosEnd() *tools.Position {
+	return n.Pos_End
+}
+
 type WhileNode struct {
 	KondisiNode      Expr
 	BodyNode         Expr
@@ -562,6 +741,9 @@ func (n DictionaryNode) GetPosEnd() *tools.Position {
 type ArrayTypeNode struct {
 	StartNode Expr
 	EndNode   Expr
+	// Start and End are the values of the bounds, worked out while parsing.
+	Start     int
+	End       int
 	OfType    Expr
 	Pos_Start *tools.Position
 	Pos_End   *tools.Position
@@ -581,9 +763,223 @@ func (n ArrayTypeNode) GetPosEnd() *tools.Position {
 	return n.Pos_End
 }
 
+// FileTypeNode is `file of T`, or `text` when OfType is nil.
+type FileTypeNode struct {
+	OfType    Expr
+	Pos_Start *tools.Position
+	Pos_End   *tools.Position
+}
+
+func (n FileTypeNode) expr() {}
+func (n FileTypeNode) Print() string {
+	return PrintValueAST(n)
+}
+func (n FileTypeNode) Name() string {
+	return "FileTypeNode"
+}
+func (n FileTypeNode) GetPosStart() *tools.Position {
+	return n.Pos_Start
+}
+func (n FileTypeNode) GetPosEnd() *tools.Position {
+	return n.Pos_End
+}
+
+// ListTypeNode is `list of T`, a list that starts empty and grows.
+type ListTypeNode struct {
+	OfType    Expr
+	Pos_Start *tools.Position
+	Pos_End   *tools.Position
+}
+
+func (n ListTypeNode) expr() {}
+func (n ListTypeNode) Print() string {
+	return PrintValueAST(n)
+}
+func (n ListTypeNode) Name() string {
+	return "ListTypeNode"
+}
+func (n ListTypeNode) GetPosStart() *tools.Position {
+	return n.Pos_Start
+}
+func (n ListTypeNode) GetPosEnd() *tools.Position {
+	return n.Pos_End
+}
+
+// EnumTypeNode is `type Day : (Mon, Tue, Wed)`. It only appears as the
+// target of a type declaration, so it carries the name it is declared under.
+type EnumTypeNode struct {
+	TypeTok   lexer.Token
+	Values    []lexer.Token
+	Pos_Start *tools.Position
+	Pos_End   *tools.Position
+}
+
+func (n EnumTypeNode) expr() {}
+func (n EnumTypeNode) Print() string {
+	return PrintValueAST(n)
+}
+func (n EnumTypeNode) Name() string {
+	return "EnumTypeNode"
+}
+func (n EnumTypeNode) GetPosStart() *tools.Position {
+	return n.Pos_Start
+}
+func (n EnumTypeNode) GetPosEnd() *tools.Position {
+	return n.Pos_End
+}
+
+// NamaNilai returns the names of the values in declaration order.
+func (n EnumTypeNode) NamaNilai() []string {
+	hasil := make([]string, len(n.Values))
+	for i, v := range n.Values {
+		hasil[i] = v.Value
+	}
+	return hasil
+}
+
+// SubrangeTypeNode is `0..100` or `Mon..Fri`, the values from Low to High of
+// an ordinal type.
+type SubrangeTypeNode struct {
+	Low       Expr
+	High      Expr
+	Pos_Start *tools.Position
+	Pos_End   *tools.Position
+}
+
+func (n SubrangeTypeNode) expr() {}
+func (n SubrangeTypeNode) Print() string {
+	return PrintValueAST(n)
+}
+func (n SubrangeTypeNode) Name() string {
+	return "SubrangeTypeNode"
+}
+func (n SubrangeTypeNode) GetPosStart() *tools.Position {
+	return n.Pos_Start
+}
+func (n SubrangeTypeNode) GetPosEnd() *tools.Position {
+	return n.Pos_End
+}
+
+// PointerTypeNode is `^T` or `pointer to T`.
+type PointerTypeNode struct {
+	OfType    Expr
+	Pos_Start *tools.Position
+	Pos_End   *tools.Position
+}
+
+func (n PointerTypeNode) expr() {}
+func (n PointerTypeNode) Print() string {
+	return PrintValueAST(n)
+}
+func (n PointerTypeNode) Name() string {
+	return "PointerTypeNode"
+}
+func (n PointerTypeNode) GetPosStart() *tools.Position {
+	return n.Pos_Start
+}
+func (n PointerTypeNode) GetPosEnd() *tools.Position {
+	return n.Pos_End
+}
+
+// DerefNode is `p^`, the value the pointer p points to.
+type DerefNode struct {
+	Pointer   Expr
+	Pos_Start *tools.Position
+	Pos_End   *tools.Position
+}
+
+func (n DerefNode) expr() {}
+func (n DerefNode) Print() string {
+	return PrintValueAST(n)
+}
+func (n DerefNode) Name() string {
+	return "DerefNode"
+}
+func (n DerefNode) GetPosStart() *tools.Position {
+	return n.Pos_Start
+}
+func (n DerefNode) GetPosEnd() *tools.Position {
+	return n.Pos_End
+}
+
+// DerefAssignNode is `p^ <- value`.
+type DerefAssignNode struct {
+	Deref     DerefNode
+	ValueNode Expr
+	Pos_Start *tools.Position
+	Pos_End   *tools.Position
+}
+
+func (n DerefAssignNode) expr() {}
+func (n DerefAssignNode) Print() string {
+	return PrintValueAST(n)
+}
+func (n DerefAssignNode) Name() string {
+	return "DerefAssignNode"
+}
+func (n DerefAssignNode) GetPosStart() *tools.Position {
+	return n.Pos_Start
+}
+func (n DerefAssignNode) GetPosEnd() *tools.Position {
+	return n.Pos_End
+}
+
+// SliceNode is `x[a..b]`, the elements or characters a to b of x.
+type SliceNode struct {
+	Left      Expr
+	Low       Expr
+	High      Expr
+	Pos_Start *tools.Position
+	Pos_End   *tools.Position
+}
+
+func (n SliceNode) expr() {}
+func (n SliceNode) Print() string {
+	return PrintValueAST(n)
+}
+func (n SliceNode) Name() string {
+	return "SliceNode"
+}
+func (n SliceNode) GetPosStart() *tools.Position {
+	return n.Pos_Start
+}
+func (n SliceNode) GetPosEnd() *tools.Position {
+	return n.Pos_End
+}
+
+// StructLiteralNode is `Point<x: 1, y: 2>`, which sets the named fields and
+// leaves the rest at their defaults, or `Point(1, 2)`, which gives every field
+// in declaration order.
+type StructLiteralNode struct {
+	TypeTok    lexer.Token
+	Fields     []VarAssignNode // Point<x: 1>: the field and its value
+	Values     []Expr          // Point(1, 2)
+	Positional bool
+	Pos_Start  *tools.Position
+	Pos_End    *tools.Position
+}
+
+func (n StructLiteralNode) expr() {}
+func (n StructLiteralNode) Print() string {
+	return PrintValueAST(n)
+}
+func (n StructLiteralNode) Name() string {
+	return "StructLiteralNode"
+}
+func (n StructLiteralNode) GetPosStart() *tools.Position {
+	return n.Pos_Start
+}
+func (n StructLiteralNode) GetPosEnd() *tools.Position {
+	return n.Pos_End
+}
+
+// ArrayIndexNode is `x[i]`. `m[i, j]` is parsed as `m[i][j]`, with
+// Dimension telling which index of the list each node is (1, 2, ...); it is
+// 0 for an index written on its own.
 type ArrayIndexNode struct {
 	Left      Expr
 	Index     Expr
+	Dimension int
 	Pos_Start *tools.Position
 	Pos_End   *tools.Position
 }
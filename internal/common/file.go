@@ -0,0 +1,417 @@
+package common
+
+import (
+	"bufio"
+	"dap/tools"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FolderBerkas is the directory programs keep their files in, set with
+// --files. The names given to assign are relative to it and can't leave it.
+var FolderBerkas = "."
+
+// berkasTerbuka holds the files open for writing, so what they buffered is
+// saved even when the program doesn't close them.
+var berkasTerbuka = map[*berkas]bool{}
+
+type berkas struct {
+	nama   string
+	mode   string
+	handle *os.File
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+// File is a variable of type `file of T` or `text`. Elemen is the resolved T,
+// with the fields of a struct resolved too, and nil for a text file. Copies
+// share the open file, as they do in Pascal.
+type File struct {
+	Elemen    Expr
+	state     *berkas
+	Context   *Context
+	Pos_Start *tools.Position
+	Pos_End   *tools.Position
+}
+
+func NewFile(elemen Expr) File {
+	return File{Elemen: elemen, state: &berkas{}}
+}
+
+func (n File) Print() string {
+	return PrintValueInterpreter(n)
+}
+
+func (n File) Set_pos(Pos_Start *tools.Position, Pos_End *tools.Position) Value {
+	n.Pos_Start = Pos_Start
+	n.Pos_End = Pos_End
+	return n
+}
+
+func (n File) Set_context(context *Context) Value {
+	n.Context = context
+	return n
+}
+
+func (n File) Get_context() *Context {
+	return n.Context
+}
+
+func (n File) Copy() Value {
+	return n
+}
+
+func (n File) Is_true() bool {
+	return true
+}
+
+// Nama is the name given by assign, or "" before that.
+func (n File) Nama() string {
+	return n.state.nama
+}
+
+// ApakahText reports a text file, whose content is read and written as text
+// rather than one element per line.
+func (n File) ApakahText() bool {
+	return n.Elemen == nil
+}
+
+// sebutan names the file in error messages.
+func (n File) sebutan() string {
+	if n.state.nama == "" {
+		return "the file"
+	}
+	return fmt.Sprintf("file '%s'", n.state.nama)
+}
+
+// Pembaca returns the reader of a file open for reading.
+func (n File) Pembaca(fungsi string) (*bufio.Reader, error) {
+	if n.state.mode != "r" {
+		return nil, fmt.Errorf("%s: %s is not open for reading", fungsi, n.sebutan())
+	}
+	return n.state.reader, nil
+}
+
+// penulis returns the writer of a file open for writing or appending.
+func (n File) penulis(fungsi string) (*bufio.Writer, error) {
+	if n.state.mode != "w" && n.state.mode != "a" {
+		return nil, fmt.Errorf("%s: %s is not open for writing", fungsi, n.sebutan())
+	}
+	return n.state.writer, nil
+}
+
+// SimpanBerkas writes out what the open files still buffer.
+func SimpanBerkas() {
+	for b := range berkasTerbuka {
+		b.writer.Flush()
+	}
+}
+
+// jalurBerkas turns a name given to assign into a path inside FolderBerkas.
+func jalurBerkas(nama string) (string, error) {
+	if !filepath.IsLocal(nama) {
+		return "", fmt.Errorf("'%s' is outside the files directory, use a name relative to it", nama)
+	}
+
+	folder, err := filepath.EvalSymlinks(FolderBerkas)
+	if err != nil {
+		return "", fmt.Errorf("the files directory '%s' can't be used: %v", FolderBerkas, errors.Unwrap(err))
+	}
+	jalur := filepath.Join(folder, nama)
+
+	// A link inside the directory mustn't lead out of it either.
+	asli, err := filepath.EvalSymlinks(jalur)
+	if err != nil {
+		asli, err = filepath.EvalSymlinks(filepath.Dir(jalur))
+	}
+	if err == nil {
+		if rel, err := filepath.Rel(folder, asli); err != nil || !(rel == "." || filepath.IsLocal(rel)) {
+			return "", fmt.Errorf("'%s' is outside the files directory, use a name relative to it", nama)
+		}
+	}
+
+	return jalur, nil
+}
+
+func (n BuiltInFunction) ambilFile(ctx *Context, fungsi string) (File, Value) {
+	f, ok := ctx.Symbol_Table.Get("file").(File)
+	if !ok {
+		res := &RTResult{}
+		return f, res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%s expects a file, got %s", fungsi, NamaTipe(ctx.Symbol_Table.Get("file"))), ctx))
+	}
+	return f, nil
+}
+
+// ExecuteAssign names the file a file variable stands for: assign(f, "data.txt").
+func (n BuiltInFunction) ExecuteAssign() ([]string, func(*Context, []Expr) Value) {
+	return []string{"file", "name"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		f, gagal := n.ambilFile(ctx, "assign")
+		if gagal != nil {
+			return gagal
+		}
+		nama, ok := ctx.Symbol_Table.Get("name").(String)
+		if !ok || nama.Value == "" {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, "assign expects the name of the file as a string", ctx))
+		}
+		if f.state.mode != "" {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("assign: close '%s' before giving the file another name", f.state.nama), ctx))
+		}
+
+		f.state.nama = nama.Value
+		return res.Success(Null{})
+	}
+}
+
+// ExecuteOpen opens an assigned file: open(f) or open(f, "r") to read it,
+// open(f, "w") to write it anew and open(f, "a") to add to its end.
+func (n BuiltInFunction) ExecuteOpen() ([]string, func(*Context, []Expr) Value) {
+	return []string{"file", "...mode"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		f, gagal := n.ambilFile(ctx, "open")
+		if gagal != nil {
+			return gagal
+		}
+
+		mode := "r"
+		switch args := ctx.Symbol_Table.Get("mode").(List).Elements; len(args) {
+		case 0:
+		case 1:
+			teks, ok := args[0].(String)
+			if !ok || (teks.Value != "r" && teks.Value != "w" && teks.Value != "a") {
+				return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("open: the mode must be \"r\", \"w\" or \"a\", got %s", PrintValueInterpreter(args[0])), ctx))
+			}
+			mode = teks.Value
+		default:
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, "open takes a file and a mode", ctx))
+		}
+
+		if f.state.nama == "" {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, "open: give the file a name with assign first", ctx))
+		}
+		if f.state.mode != "" {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("open: file '%s' is already open", f.state.nama), ctx))
+		}
+
+		jalur, err := jalurBerkas(f.state.nama)
+		if err != nil {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, "open: "+err.Error(), ctx))
+		}
+
+		var handle *os.File
+		switch mode {
+		case "r":
+			handle, err = os.Open(jalur)
+		case "w":
+			handle, err = os.Create(jalur)
+		case "a":
+			handle, err = os.OpenFile(jalur, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		}
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("open: there is no file '%s'", f.state.nama), ctx))
+			}
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("open: cannot open '%s': %v", f.state.nama, errors.Unwrap(err)), ctx))
+		}
+
+		f.state.mode = mode
+		f.state.handle = handle
+		if mode == "r" {
+			f.state.reader = bufio.NewReader(handle)
+		} else {
+			f.state.writer = bufio.NewWriter(handle)
+			berkasTerbuka[f.state] = true
+		}
+
+		return res.Success(Null{})
+	}
+}
+
+// ExecuteClose saves and closes an open file. It keeps its name, so it can be
+// opened again, e.g. to read back what was written.
+func (n BuiltInFunction) ExecuteClose() ([]string, func(*Context, []Expr) Value) {
+	return []string{"file"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		f, gagal := n.ambilFile(ctx, "close")
+		if gagal != nil {
+			return gagal
+		}
+		if f.state.mode == "" {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("close: %s is not open", f.sebutan()), ctx))
+		}
+
+		var err error
+		if f.state.writer != nil {
+			err = f.state.writer.Flush()
+			delete(berkasTerbuka, f.state)
+		}
+		if errClose := f.state.handle.Close(); err == nil {
+			err = errClose
+		}
+		f.state.mode, f.state.handle, f.state.reader, f.state.writer = "", nil, nil, nil
+
+		if err != nil {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("close: cannot save '%s': %v", f.state.nama, err), ctx))
+		}
+		return res.Success(Null{})
+	}
+}
+
+// tulisKeBerkas is write(f, ...) and writeln(f, ...). A text file gets the
+// arguments as write prints them; a file of T gets each one as an element on
+// a line of its own.
+func (n BuiltInFunction) tulisKeBerkas(ctx *Context, f File, args []Value, perBaris bool) Value {
+	res := &RTResult{}
+	fungsi := "write"
+	if perBaris {
+		fungsi = "writeln"
+	}
+
+	w, err := f.penulis(fungsi)
+	if err != nil {
+		return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, err.Error(), ctx))
+	}
+
+	if f.ApakahText() {
+		w.WriteString(gabungArgumen(args))
+		if perBaris {
+			w.WriteString("\n")
+		}
+		return res.Success(Null{})
+	}
+
+	if perBaris {
+		return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, "writeln is for text files, write puts every element of a file of "+f.Elemen.Print()+" on its own line already", ctx))
+	}
+	for _, v := range args {
+		baris, err := tulisElemen(f.Elemen, v)
+		if err != nil {
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("write: %v", err), ctx))
+		}
+		w.WriteString(baris + "\n")
+	}
+
+	return res.Success(Null{})
+}
+
+// tulisElemen renders one element of a file of T: numbers as they print,
+// strings quoted and the fields of a struct in declaration order, separated by
+// spaces.
+func tulisElemen(tipe Expr, v Value) (string, error) {
+	switch t := tipe.(type) {
+	case StructTypeNode:
+		s, ok := v.(Struct)
+		if !ok {
+			return "", fmt.Errorf("a file of %s can't hold %s", t.StructName.Value, NamaTipe(v))
+		}
+		bagian := make([]string, len(t.Fields))
+		for i, field := range t.Fields {
+			teks, err := tulisElemen(field.ValueNode, s.Fields[field.VarName.Value])
+			if err != nil {
+				return "", err
+			}
+			bagian[i] = teks
+		}
+		return strings.Join(bagian, " "), nil
+	case VarAccessNode:
+		switch t.VarNameTok.Value {
+		case "integer", "real":
+			number, ok := v.(Number)
+			if !ok || (t.VarNameTok.Value == "integer" && number.Value != float64(int64(number.Value))) {
+				break
+			}
+			return PrintValueInterpreter(number), nil
+		case "string", "character":
+			teks, ok := v.(String)
+			if !ok {
+				break
+			}
+			return strconv.Quote(teks.Value), nil
+		}
+		return "", fmt.Errorf("a file of %s can't hold %s", t.VarNameTok.Value, PrintValueInterpreter(v))
+	}
+
+	return "", fmt.Errorf("a file can't hold %s", NamaTipe(v))
+}
+
+// BacaElemen reads the next element of a file of T. Blank lines are skipped,
+// and a string may be written with or without quotes; without them it runs to
+// the end of the line, or to the next space inside a struct.
+func (n File) BacaElemen(fungsi string) (Value, error) {
+	r, err := n.Pembaca(fungsi)
+	if err != nil {
+		return nil, err
+	}
+	if !lewatiSpasi(r) {
+		return nil, fmt.Errorf("%s: no more elements in file '%s'", fungsi, n.state.nama)
+	}
+
+	baris, _ := r.ReadString('\n')
+	baris = strings.TrimSpace(baris)
+	_, satuString := n.Elemen.(VarAccessNode)
+
+	value, sisa, err := bacaElemen(n.Elemen, baris, satuString)
+	if err == nil && strings.TrimSpace(sisa) != "" {
+		err = fmt.Errorf("unexpected %q at the end of the line", strings.TrimSpace(sisa))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: bad element %q in file '%s': %v", fungsi, baris, n.state.nama, err)
+	}
+	return value, nil
+}
+
+func bacaElemen(tipe Expr, baris string, satuBaris bool) (Value, string, error) {
+	baris = strings.TrimLeft(baris, " \t")
+
+	switch t := tipe.(type) {
+	case StructTypeNode:
+		fields := make(map[string]Value, len(t.Fields))
+		for _, field := range t.Fields {
+			value, sisa, err := bacaElemen(field.ValueNode, baris, false)
+			if err != nil {
+				return nil, "", err
+			}
+			fields[field.VarName.Value] = value
+			baris = sisa
+		}
+		return Struct{Nama: t.StructName.Value, Fields: fields, Urutan: t.NamaField()}, baris, nil
+	case VarAccessNode:
+		namaTipe := t.VarNameTok.Value
+		if baris == "" {
+			return nil, "", fmt.Errorf("missing a %s", namaTipe)
+		}
+
+		var teks string
+		if (namaTipe == "string" || namaTipe == "character") && strings.HasPrefix(baris, `"`) {
+			quoted, err := strconv.QuotedPrefix(baris)
+			if err != nil {
+				return nil, "", fmt.Errorf("unterminated string")
+			}
+			teks, _ = strconv.Unquote(quoted)
+			baris = baris[len(quoted):]
+		} else if namaTipe == "string" && satuBaris {
+			teks, baris = baris, ""
+		} else {
+			akhir := strings.IndexAny(baris, " \t")
+			if akhir < 0 {
+				akhir = len(baris)
+			}
+			teks, baris = baris[:akhir], baris[akhir:]
+		}
+
+		value, pesan := UbahTeks(teks, namaTipe)
+		if pesan != "" {
+			return nil, "", fmt.Errorf("%q is not %s", teks, pesan)
+		}
+		return value, baris, nil
+	}
+
+	return nil, "", fmt.Errorf("a file can't hold %s", tipe.Print())
+}
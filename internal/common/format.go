@@ -0,0 +1,114 @@
+package common
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// barisTerbuka is set while the last thing written didn't end the line, so
+// whatever is printed next (an error, the trace) can start on a new one.
+var barisTerbuka bool
+
+// Tulis writes text to the program's output.
+func Tulis(text string) {
+	if text == "" {
+		return
+	}
+	fmt.Print(text)
+	barisTerbuka = !strings.HasSuffix(text, "\n")
+}
+
+// TutupBaris ends the output line left open by write.
+func TutupBaris() {
+	if barisTerbuka {
+		fmt.Println()
+		barisTerbuka = false
+	}
+}
+
+// FormatKolom formats an argument of write(x:width:precision) like Pascal: the
+// text is right-aligned in at least width characters, and a number with a
+// precision gets exactly that many decimals. A negative precision means none
+// was given.
+func FormatKolom(value Value, width int, precision int) (string, error) {
+	if width < 0 {
+		return "", fmt.Errorf("field width must not be negative, got %d", width)
+	}
+
+	text := PrintValueInterpreter(value)
+	if precision >= 0 {
+		number, ok := value.(Number)
+		if !ok {
+			return "", fmt.Errorf("only a number can be written with decimals, got %s", NamaTipe(value))
+		}
+		text = fmt.Sprintf("%.*f", precision, number.Value)
+	}
+
+	return fmt.Sprintf("%*s", width, text), nil
+}
+
+// FormatTeks fills a format string the way format() does. It understands a
+// subset of printf: %d, %f, %s and %%, with the flags '-' and '0', a width and
+// a precision, e.g. "%-10s|%6.2f".
+func FormatTeks(format string, args []Value) (string, error) {
+	var hasil strings.Builder
+	next := 0
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			hasil.WriteByte(format[i])
+			continue
+		}
+
+		j := i + 1
+		for j < len(format) && strings.ContainsRune("-0123456789.", rune(format[j])) {
+			j++
+		}
+		if j == len(format) {
+			return "", fmt.Errorf("format %q ends in the middle of a placeholder", format)
+		}
+
+		spec, verb := format[i+1:j], format[j]
+		i = j
+
+		if verb == '%' && spec == "" {
+			hasil.WriteByte('%')
+			continue
+		}
+		if strings.Count(spec, ".") > 1 {
+			return "", fmt.Errorf("bad placeholder %%%s%c in format", spec, verb)
+		}
+
+		if next >= len(args) {
+			return "", fmt.Errorf("format has more placeholders than arguments")
+		}
+		arg := args[next]
+		next++
+
+		switch verb {
+		case 'd':
+			number, ok := arg.(Number)
+			if !ok || number.Value != math.Trunc(number.Value) {
+				return "", fmt.Errorf("%%d expects an integer, got %s", PrintValueInterpreter(arg))
+			}
+			hasil.WriteString(fmt.Sprintf("%"+spec+"d", int64(number.Value)))
+		case 'f':
+			number, ok := arg.(Number)
+			if !ok {
+				return "", fmt.Errorf("%%f expects a number, got %s", NamaTipe(arg))
+			}
+			hasil.WriteString(fmt.Sprintf("%"+spec+"f", number.Value))
+		case 's':
+			hasil.WriteString(fmt.Sprintf("%"+spec+"s", PrintValueInterpreter(arg)))
+		default:
+			return "", fmt.Errorf("unknown placeholder %%%s%c in format, expected %%d, %%f, %%s or %%%%", spec, verb)
+		}
+	}
+
+	if next < len(args) {
+		return "", fmt.Errorf("format has more arguments than placeholders")
+	}
+
+	return hasil.String(), nil
+}
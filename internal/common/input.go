@@ -0,0 +1,127 @@
+package common
+
+import (
+	"bufio"
+	"dap/tools"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Masukan is the program's input. read, readln and eof share it with the
+// console, so none of them buffers lines the others then miss.
+var Masukan = bufio.NewReader(os.Stdin)
+
+// lewatiSpasi skips whitespace and reports false at the end of the input.
+func lewatiSpasi(masukan *bufio.Reader) bool {
+	for {
+		r, _, err := masukan.ReadRune()
+		if err != nil {
+			return false
+		}
+		if !unicode.IsSpace(r) {
+			masukan.UnreadRune()
+			return true
+		}
+	}
+}
+
+// BacaKata reads the next whitespace-separated item, on this line or the
+// following ones. It returns io.EOF when the input has run out.
+func BacaKata(masukan *bufio.Reader) (string, error) {
+	if !lewatiSpasi(masukan) {
+		return "", io.EOF
+	}
+
+	var hasil strings.Builder
+	for {
+		r, _, err := masukan.ReadRune()
+		if err != nil {
+			break
+		}
+		if unicode.IsSpace(r) {
+			masukan.UnreadRune()
+			break
+		}
+		hasil.WriteRune(r)
+	}
+	return hasil.String(), nil
+}
+
+// BacaSisaBaris reads what is left of the current line, without the newline
+// and the spaces around it.
+func BacaSisaBaris(masukan *bufio.Reader) (string, error) {
+	baris, err := masukan.ReadString('\n')
+	if err != nil && baris == "" {
+		return "", io.EOF
+	}
+	return strings.TrimSpace(baris), nil
+}
+
+// ApakahEOF reports whether only whitespace is left in the input.
+func ApakahEOF(masukan *bufio.Reader) bool {
+	return !lewatiSpasi(masukan)
+}
+
+// UbahTeks parses what was read for a declared type. Without a known type a
+// number is taken as a number and anything else as a string. The message is
+// empty on success and otherwise names what was expected.
+func UbahTeks(teks string, namaTipe string) (Value, string) {
+	switch namaTipe {
+	case "integer":
+		number, err := strconv.ParseInt(teks, 10, 64)
+		if err != nil {
+			return nil, "an integer"
+		}
+		return Number{Value: float64(number)}, ""
+	case "real":
+		number, err := strconv.ParseFloat(teks, 64)
+		if err != nil {
+			return nil, "a number"
+		}
+		return Number{Value: number}, ""
+	case "character":
+		if len([]rune(teks)) != 1 {
+			return nil, "a single character"
+		}
+		return String{Value: teks}, ""
+	case "string":
+		return String{Value: teks}, ""
+	}
+
+	if number, err := strconv.ParseFloat(teks, 64); err == nil {
+		return Number{Value: number}, ""
+	}
+	return String{Value: teks}, ""
+}
+
+// ExecuteEof is true once only whitespace is left to read, so a loop like
+// `while !eof() do read x` stops before a trailing newline. eof(f) asks the
+// same of a file opened for reading.
+func (n BuiltInFunction) ExecuteEof() ([]string, func(*Context, []Expr) Value) {
+	return []string{"...file"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		masukan := Masukan
+		switch args := ctx.Symbol_Table.Get("file").(List).Elements; len(args) {
+		case 0:
+		case 1:
+			f, ok := args[0].(File)
+			if !ok {
+				return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("eof expects a file, got %s", NamaTipe(args[0])), ctx))
+			}
+			r, err := f.Pembaca("eof")
+			if err != nil {
+				return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, err.Error(), ctx))
+			}
+			masukan = r
+		default:
+			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, "eof takes at most one file", ctx))
+		}
+
+		return res.Success(Number{Value: float64(tools.GetComparison(ApakahEOF(masukan)))})
+	}
+}
@@ -0,0 +1,92 @@
+package common
+
+import (
+	"dap/tools"
+)
+
+// Pointer is a value of a pointer type ^T. Copies of a pointer point to the
+// same place, so a change through one is seen through all of them. nil is
+// the Pointer without a place; Tipe is the T it points to, when known.
+type Pointer struct {
+	tempat    *tempatPointer
+	Tipe      Expr
+	Context   *Context
+	Pos_Start *tools.Position
+	Pos_End   *tools.Position
+}
+
+// tempatPointer is the place made by new, until dispose frees it.
+type tempatPointer struct {
+	nilai   Value
+	dibuang bool
+}
+
+// BuatPointer is what new gives: a pointer to a new place holding nilai.
+func BuatPointer(tipe Expr, nilai Value) Pointer {
+	return Pointer{tempat: &tempatPointer{nilai: nilai}, Tipe: tipe}
+}
+
+func (n Pointer) ApakahNil() bool {
+	return n.tempat == nil
+}
+
+// Isi is the value pointed to. pesan says why there is none: the pointer is
+// nil or its place was disposed.
+func (n Pointer) Isi() (nilai Value, pesan string) {
+	switch {
+	case n.tempat == nil:
+		return nil, "is nil"
+	case n.tempat.dibuang:
+		return nil, "points to memory that was disposed"
+	}
+	return n.tempat.nilai, ""
+}
+
+// Ganti changes the value pointed to, which Isi must have found first.
+func (n Pointer) Ganti(nilai Value) {
+	n.tempat.nilai = nilai
+}
+
+// Buang frees the place pointed to, for dispose. Every pointer to it is left
+// dangling, and using one of them is an error.
+func (n Pointer) Buang() (pesan string) {
+	if _, pesan := n.Isi(); pesan != "" {
+		return pesan
+	}
+	n.tempat.nilai = nil
+	n.tempat.dibuang = true
+	return ""
+}
+
+func (n Pointer) Print() string {
+	if n.tempat == nil {
+		return "nil"
+	}
+	if n.Tipe == nil {
+		return "^"
+	}
+	return "^" + n.Tipe.Print()
+}
+
+func (n Pointer) Set_pos(Pos_Start *tools.Position, Pos_End *tools.Position) Value {
+	n.Pos_Start = Pos_Start
+	n.Pos_End = Pos_End
+	return n
+}
+
+func (n Pointer) Set_context(context *Context) Value {
+	n.Context = context
+	return n
+}
+
+func (n Pointer) Get_context() *Context {
+	return n.Context
+}
+
+func (n Pointer) Copy() Value {
+	return n
+}
+
+func (n Pointer) Is_true() bool {
+	return n.tempat != nil
+}
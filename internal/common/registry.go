@@ -0,0 +1,161 @@
+package common
+
+import (
+	"dap/tools"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Param describes one parameter of a registered built-in. A variadic
+// parameter must be the last one and takes zero or more arguments.
+type Param struct {
+	Name     string
+	Variadic bool
+}
+
+// BuiltinFunc is the Go side of a registered built-in. args holds the
+// arguments in call order, already checked against the parameters. A returned
+// error becomes a runtime error at the call.
+type BuiltinFunc func(ctx *Context, args []Value) (Value, error)
+
+type Builtin struct {
+	Name    string
+	Aliases []string
+	Params  []Param
+	Doc     string
+	Fn      BuiltinFunc
+}
+
+// BuiltinOption sets the optional parts of a registered built-in.
+type BuiltinOption func(*Builtin)
+
+// Alias makes the built-in callable under other names as well.
+func Alias(names ...string) BuiltinOption {
+	return func(b *Builtin) {
+		b.Aliases = append(b.Aliases, names...)
+	}
+}
+
+// Doc attaches a one-line description, shown by `dap --help`.
+func Doc(doc string) BuiltinOption {
+	return func(b *Builtin) {
+		b.Doc = doc
+	}
+}
+
+var daftarBuiltin = map[string]*Builtin{}
+
+// kataTerdaftar maps the names and aliases of registered built-ins to the
+// name each was registered under. DAP's own built-ins keep theirs in
+// tools.SemuaBuiltInFunction, mapped to the suffix of their Execute method.
+var kataTerdaftar = map[string]string{}
+
+// RegisterBuiltin adds a built-in implemented in Go without touching the
+// interpreter. Code outside this module reaches it through dap/builtin.
+//
+// It must run before IsiGlobal fills the global symbol table. Registering a name
+// again replaces the earlier built-in, including the ones that come with DAP.
+func RegisterBuiltin(name string, params []Param, fn BuiltinFunc, opsi ...BuiltinOption) *Builtin {
+	if name == "" || fn == nil {
+		panic("RegisterBuiltin: a built-in needs a name and a function")
+	}
+	for i, p := range params {
+		if p.Variadic && i != len(params)-1 {
+			panic(fmt.Sprintf("RegisterBuiltin: variadic parameter '%s' of '%s' must be the last one", p.Name, name))
+		}
+	}
+
+	b := &Builtin{Name: name, Params: params, Fn: fn}
+	for _, o := range opsi {
+		o(b)
+	}
+
+	daftarBuiltin[name] = b
+	for _, keyword := range append([]string{name}, b.Aliases...) {
+		tools.SemuaBuiltInFunction[keyword] = name
+		kataTerdaftar[keyword] = name
+	}
+
+	return b
+}
+
+// IsiGlobal puts what every program starts with into tabel: null, nil, true,
+// false, the basic types and the built-ins, registered ones included.
+func IsiGlobal(tabel *SymbolTable) {
+	tabel.Set("null", Null{})
+	tabel.Set("nil", Pointer{})
+	tabel.Set("true", Number{Value: 1})
+	tabel.Set("false", Number{Value: 0})
+	tabel.Set("integer", Number{Value: 0})
+	tabel.Set("real", Number{Value: 0})
+	tabel.Set("string", String{Value: ""})
+	tabel.Set("character", String{Value: ""})
+
+	for keyword, nama := range tools.SemuaBuiltInFunction {
+		_, terdaftar := kataTerdaftar[keyword]
+		tabel.Set(keyword, BuiltInFunction{
+			BaseFunction: BaseFunction{
+				Name: nama,
+			},
+			Terdaftar: terdaftar,
+		})
+	}
+}
+
+// CariBuiltin finds a registered built-in by its name or one of its aliases.
+func CariBuiltin(name string) (*Builtin, bool) {
+	b, ok := daftarBuiltin[kataTerdaftar[name]]
+	return b, ok
+}
+
+// SemuaBuiltin returns the registered built-ins sorted by name.
+func SemuaBuiltin() []*Builtin {
+	hasil := make([]*Builtin, 0, len(daftarBuiltin))
+	for _, b := range daftarBuiltin {
+		hasil = append(hasil, b)
+	}
+	sort.Slice(hasil, func(i, j int) bool {
+		return hasil[i].Name < hasil[j].Name
+	})
+	return hasil
+}
+
+// Signature renders the call form, e.g. forward(steps) or concat(...texts).
+func (b *Builtin) Signature() string {
+	return fmt.Sprintf("%s(%s)", b.Name, strings.Join(b.namaArgumen(), ", "))
+}
+
+// namaArgumen lists the parameters the way CheckArgs expects them.
+func (b *Builtin) namaArgumen() []string {
+	hasil := make([]string, len(b.Params))
+	for i, p := range b.Params {
+		hasil[i] = p.Name
+		if p.Variadic {
+			hasil[i] = "..." + p.Name
+		}
+	}
+	return hasil
+}
+
+// executeRegistered runs a registered built-in for BuiltInFunction.Execute.
+func (n BuiltInFunction) executeRegistered(b *Builtin, args []Value) Value {
+	res := &RTResult{}
+	execCtx := n.GenerateNewContext()
+
+	res.Register(n.CheckAndPopulateArgs(b.namaArgumen(), args, &execCtx))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	hasil, err := b.Fn(&execCtx, args)
+	if err != nil {
+		return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, err.Error(), &execCtx))
+	}
+	if hasil == nil {
+		hasil = Null{}
+	}
+
+	// A value carrying a context would be taken for variables to write back.
+	return res.Success(hasil.Set_context(nil))
+}
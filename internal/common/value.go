@@ -56,10 +56,6 @@ func PrintValueInterpreter(n Value) string {
 	case String:
 		return n.Value
 	case List:
-		if len(n.Elements) == 1 {
-			return PrintValueInterpreter(n.Elements[0])
-		}
-
 		hasil := "["
 		for i, v := range n.Elements {
 			hasil += PrintValueInterpreter(v)
@@ -83,11 +79,14 @@ func PrintValueInterpreter(n Value) string {
 		return fmt.Sprintf("<function %s>", n.Name)
 	case Struct:
 		hasil := "<"
-		keys := make([]string, 0, len(n.Fields))
-		for k := range n.Fields {
-			keys = append(keys, k)
+		keys := n.Urutan
+		if len(keys) != len(n.Fields) {
+			keys = make([]string, 0, len(n.Fields))
+			for k := range n.Fields {
+				keys = append(keys, k)
+			}
+			slices.Sort(keys)
 		}
-		slices.Sort(keys)
 		for i, k := range keys {
 			hasil += fmt.Sprintf("%s: %v", k, PrintValueInterpreter(n.Fields[k]))
 			if i < len(keys)-1 {
@@ -96,8 +95,15 @@ func PrintValueInterpreter(n Value) string {
 		}
 		hasil += ">"
 		return hasil
+	case Enum, Pointer:
+		return n.Print()
 	case Type:
 		return "<type>"
+	case File:
+		if n.Nama() == "" {
+			return "<file>"
+		}
+		return fmt.Sprintf("<file %s>", n.Nama())
 	case Null:
 		return "NULL"
 	case *RTResult:
@@ -116,6 +122,98 @@ type Value interface {
 	Print() string
 }
 
+// ApakahSama compares two values structurally: arrays element by element with
+// the same bounds, structs field by field. Values of different types are never
+// equal.
+func ApakahSama(a, b Value) bool {
+	switch a := a.(type) {
+	case Number:
+		other, ok := b.(Number)
+		return ok && a.Value == other.Value
+	case String:
+		other, ok := b.(String)
+		return ok && a.Value == other.Value
+	case Null:
+		_, ok := b.(Null)
+		return ok
+	case Array:
+		other, ok := b.(Array)
+		if !ok || a.Start != other.Start || a.End != other.End || len(a.Elements) != len(other.Elements) {
+			return false
+		}
+		for i := range a.Elements {
+			if !ApakahSama(a.Elements[i], other.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case List:
+		other, ok := b.(List)
+		if !ok || len(a.Elements) != len(other.Elements) {
+			return false
+		}
+		for i := range a.Elements {
+			if !ApakahSama(a.Elements[i], other.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case Struct:
+		other, ok := b.(Struct)
+		if !ok || a.Nama != other.Nama || len(a.Fields) != len(other.Fields) {
+			return false
+		}
+		for k, v := range a.Fields {
+			field, ada := other.Fields[k]
+			if !ada || !ApakahSama(v, field) {
+				return false
+			}
+		}
+		return true
+	case File:
+		other, ok := b.(File)
+		return ok && a.state == other.state
+	case Enum:
+		other, ok := b.(Enum)
+		return ok && a.Tipe == other.Tipe && a.Ordinal == other.Ordinal
+	case Pointer:
+		// Pointers are equal when they point to the same place, or are both nil.
+		other, ok := b.(Pointer)
+		return ok && a.tempat == other.tempat
+	}
+
+	return false
+}
+
+// NamaTipe names the type of a value for error messages.
+func NamaTipe(v Value) string {
+	switch v := v.(type) {
+	case Enum:
+		return v.Tipe
+	case Number:
+		return "number"
+	case String:
+		return "string"
+	case Array:
+		return "array"
+	case List:
+		return "list"
+	case Struct:
+		return "struct"
+	case Null:
+		return "null"
+	case Pointer:
+		return "pointer"
+	case Function, BuiltInFunction:
+		return "function"
+	case Type:
+		return "type"
+	case File:
+		return "file"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
 type Null struct {
 	Context *Context
 }
@@ -147,6 +245,7 @@ type Array struct {
 	Context   *Context
 	Pos_Start *tools.Position
 	Pos_End   *tools.Position
+	dibagi    *bool
 }
 
 func (n Array) Print() string {
@@ -169,14 +268,8 @@ func (n Array) Get_context() *Context {
 }
 
 func (n Array) Copy() Value {
-	return Array{
-		Elements:  n.Elements,
-		Start:     n.Start,
-		End:       n.End,
-		Context:   n.Context,
-		Pos_Start: n.Pos_Start,
-		Pos_End:   n.Pos_End,
-	}
+	tandaiDibagi(n.dibagi)
+	return n
 }
 
 func (n Array) Is_true() bool {
@@ -188,6 +281,7 @@ type List struct {
 	Context   *Context
 	Pos_Start *tools.Position
 	Pos_End   *tools.Position
+	dibagi    *bool
 }
 
 func (n List) Print() string {
@@ -210,12 +304,8 @@ func (n List) Get_context() *Context {
 }
 
 func (n List) Copy() Value {
-	copy := List{}
-	copy.Elements = n.Elements
-	copy.Pos_Start = n.Pos_Start
-	copy.Pos_End = n.Pos_End
-	copy.Context = n.Context
-	return copy
+	tandaiDibagi(n.dibagi)
+	return n
 }
 
 func (n List) Is_true() bool {
@@ -223,7 +313,7 @@ func (n List) Is_true() bool {
 }
 
 func (n List) Added_to(other Value) (Value, *Error) {
-	return List{Elements: append(n.Elements, other)}.Set_context(n.Context), nil
+	return List{Elements: append(salinElemen(n.Elements), other.Copy())}.Set_context(n.Context), nil
 }
 
 func (n List) Subbed_to(other Value) (Value, *Error) {
@@ -236,7 +326,7 @@ func (n List) Subbed_to(other Value) (Value, *Error) {
 			return nil, &errorNya
 		}
 
-		return List{Elements: append(n.Elements[:angka], n.Elements[angka+1:]...)}.Set_context(n.Context), nil
+		return List{Elements: append(salinElemen(n.Elements[:angka]), salinElemen(n.Elements[angka+1:])...)}.Set_context(n.Context), nil
 	}
 
 	err := RTError(safePos(n.Pos_Start), safePos(n.Pos_End), "Illegal operation: cannot perform subtraction on List with the given type", n.Context)
@@ -246,7 +336,7 @@ func (n List) Subbed_to(other Value) (Value, *Error) {
 func (n List) Multed_by(other Value) (Value, *Error) {
 	switch other := other.(type) {
 	case List:
-		return List{Elements: append(n.Elements, other.Elements...)}.Set_context(n.Context), nil
+		return List{Elements: append(salinElemen(n.Elements), salinElemen(other.Elements)...)}.Set_context(n.Context), nil
 	}
 
 	err := RTError(safePos(n.Pos_Start), safePos(n.Pos_End), "Illegal operation: cannot perform multiplication on List with the given type", n.Context)
@@ -329,6 +419,66 @@ func (s String) Multed_by(other Value) (Value, *Error) {
 	return nil, &err
 }
 
+func (s String) Get_comparison_eq(other Value) (Value, *Error) {
+	switch other := other.(type) {
+	case String:
+		return Number{Value: float64(tools.GetComparison(s.Value == other.Value))}.Set_context(s.Context), nil
+	}
+
+	err := RTError(safePos(s.Pos_Start), safePos(s.Pos_End), "Illegal operation: cannot compare equality with the given type", s.Context)
+	return nil, &err
+}
+
+func (s String) Get_comparison_nq(other Value) (Value, *Error) {
+	switch other := other.(type) {
+	case String:
+		return Number{Value: float64(tools.GetComparison(s.Value != other.Value))}.Set_context(s.Context), nil
+	}
+
+	err := RTError(safePos(s.Pos_Start), safePos(s.Pos_End), "Illegal operation: cannot compare equality with the given type", s.Context)
+	return nil, &err
+}
+
+func (s String) Get_comparison_lt(other Value) (Value, *Error) {
+	switch other := other.(type) {
+	case String:
+		return Number{Value: float64(tools.GetComparison(s.Value < other.Value))}.Set_context(s.Context), nil
+	}
+
+	err := RTError(safePos(s.Pos_Start), safePos(s.Pos_End), "Illegal operation: cannot compare String with the given type", s.Context)
+	return nil, &err
+}
+
+func (s String) Get_comparison_lte(other Value) (Value, *Error) {
+	switch other := other.(type) {
+	case String:
+		return Number{Value: float64(tools.GetComparison(s.Value <= other.Value))}.Set_context(s.Context), nil
+	}
+
+	err := RTError(safePos(s.Pos_Start), safePos(s.Pos_End), "Illegal operation: cannot compare String with the given type", s.Context)
+	return nil, &err
+}
+
+func (s String) Get_comparison_gt(other Value) (Value, *Error) {
+	switch other := other.(type) {
+	case String:
+		return Number{Value: float64(tools.GetComparison(s.Value > other.Value))}.Set_context(s.Context), nil
+	}
+
+	err := RTError(safePos(s.Pos_Start), safePos(s.Pos_End), "Illegal operation: cannot compare String with the given type", s.Context)
+	return nil, &err
+}
+
+func (s String) Get_comparison_gte(other Value) (Value, *Error) {
+	switch other := other.(type) {
+	case String:
+		return Number{Value: float64(tools.GetComparison(s.Value >= other.Value))}.Set_context(s.Context), nil
+	}
+
+	err := RTError(safePos(s.Pos_Start), safePos(s.Pos_End), "Illegal operation: cannot compare String with the given type", s.Context)
+	return nil, &err
+}
+
 type Number struct {
 	Value     float64
 	Context   *Context
@@ -594,7 +744,7 @@ func (n BaseFunction) CheckArgs(ArgNames []string, args []Value) Value {
 	if len(args) > len(ArgNames) {
 		adaSpread := false
 		for i, v := range ArgNames {
-			if i == len(ArgNames)-1 && v[:3] == "..." {
+			if i == len(ArgNames)-1 && strings.HasPrefix(v, "...") {
 				adaSpread = true
 			}
 		}
@@ -604,8 +754,13 @@ func (n BaseFunction) CheckArgs(ArgNames []string, args []Value) Value {
 		}
 	}
 
-	if len(args) < len(ArgNames) {
-		return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%d too few args passed into '%s'", len(ArgNames)-len(args), n.Name), n.Context))
+	wajib := len(ArgNames)
+	if wajib > 0 && strings.HasPrefix(ArgNames[wajib-1], "...") {
+		wajib--
+	}
+
+	if len(args) < wajib {
+		return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, fmt.Sprintf("%d too few args passed into '%s'", wajib-len(args), n.Name), n.Context))
 	}
 
 	return res.Success(Null{})
@@ -613,10 +768,12 @@ func (n BaseFunction) CheckArgs(ArgNames []string, args []Value) Value {
 
 func (n BaseFunction) PopulateArgs(ArgNames []string, args []Value, exec_ctx *Context) {
 	for i := 0; i < len(ArgNames); i++ {
-		if i == len(ArgNames)-1 && ArgNames[i][:3] == "..." {
+		if i == len(ArgNames)-1 && strings.HasPrefix(ArgNames[i], "...") {
 			v := strings.ReplaceAll(ArgNames[i], "...", "")
 
-			args[i].Set_context(exec_ctx)
+			if i < len(args) {
+				args[i].Set_context(exec_ctx)
+			}
 			exec_ctx.Symbol_Table.Set(v, List{
 				Elements:  args[i:],
 				Context:   exec_ctx,
@@ -683,6 +840,9 @@ func (n Function) Is_true() bool {
 
 type BuiltInFunction struct {
 	BaseFunction
+	// Terdaftar marks a built-in registered from Go, whose Name is the name it
+	// was registered under rather than the suffix of an Execute method.
+	Terdaftar bool
 }
 
 func (n BuiltInFunction) Print() string {
@@ -706,6 +866,7 @@ func (n BuiltInFunction) Copy() Value {
 	copy.Pos_Start = n.Pos_Start
 	copy.Pos_End = n.Pos_End
 	copy.Context = n.Context
+	copy.Terdaftar = n.Terdaftar
 
 	return copy
 }
@@ -714,6 +875,10 @@ func (n BuiltInFunction) Is_true() bool {
 }
 
 func (n BuiltInFunction) Execute(args []Value, rawArgs []Expr) Value {
+	if b, ok := daftarBuiltin[n.Name]; ok && n.Terdaftar {
+		return n.executeRegistered(b, args)
+	}
+
 	res := &RTResult{}
 	execCtx := n.GenerateNewContext()
 
@@ -753,29 +918,52 @@ func (n BuiltInFunction) Execute(args []Value, rawArgs []Expr) Value {
 	return res.Success(hasil)
 }
 
+// ExecutePrint is writeln: its arguments side by side, then a new line. With a
+// file first, writeln(f, ...) writes them to that file instead.
 func (n BuiltInFunction) ExecutePrint() ([]string, func(*Context, []Expr) Value) {
 	return []string{"...value"}, func(ctx *Context, rawArgs []Expr) Value {
 		res := &RTResult{}
 
-		vals := ctx.Symbol_Table.Get("value")
-		var elements []Value
-		if list, ok := vals.(List); ok {
-			elements = list.Elements
-		} else if arr, ok := vals.(Array); ok {
-			elements = arr.Elements
-		} else {
-			elements = []Value{vals}
+		elements := ctx.Symbol_Table.Get("value").(List).Elements
+		if len(elements) > 0 {
+			if f, ok := elements[0].(File); ok {
+				return n.tulisKeBerkas(ctx, f, elements[1:], true)
+			}
 		}
 
-		for _, v := range elements {
-			fmt.Printf("%v\n", PrintValueInterpreter(v))
+		Tulis(gabungArgumen(elements) + "\n")
+		return res.Success(Null{})
+	}
+}
+
+// ExecuteWrite is write: like writeln but the line stays open, so a prompt or
+// the next write continues on it.
+func (n BuiltInFunction) ExecuteWrite() ([]string, func(*Context, []Expr) Value) {
+	return []string{"...value"}, func(ctx *Context, rawArgs []Expr) Value {
+		res := &RTResult{}
+
+		elements := ctx.Symbol_Table.Get("value").(List).Elements
+		if len(elements) > 0 {
+			if f, ok := elements[0].(File); ok {
+				return n.tulisKeBerkas(ctx, f, elements[1:], false)
+			}
 		}
 
-		// fmt.Printf("%v\n", PrintValueInterpreter(ctx.Symbol_Table.Get("value")))
+		Tulis(gabungArgumen(elements))
 		return res.Success(Null{})
 	}
 }
 
+// gabungArgumen puts the arguments of write next to each other without a
+// separator, as Pascal does; field widths take care of the spacing.
+func gabungArgumen(elements []Value) string {
+	var hasil strings.Builder
+	for _, v := range elements {
+		hasil.WriteString(PrintValueInterpreter(v))
+	}
+	return hasil.String()
+}
+
 func (n BuiltInFunction) ExecutePrintRet() ([]string, func(*Context, []Expr) Value) {
 	return []string{"value"}, func(ctx *Context, rawArgs []Expr) Value {
 		res := &RTResult{}
@@ -785,45 +973,16 @@ func (n BuiltInFunction) ExecutePrintRet() ([]string, func(*Context, []Expr) Val
 	}
 }
 
-func (n BuiltInFunction) ExecuteInput() ([]string, func(*Context, []Expr) Value) {
-	return []string{"...value"}, func(ctx *Context, rawArgs []Expr) Value {
-		res := &RTResult{}
-
-		//Need to improve this 20/01/2024 [23:41]
-		// Gonna improve it 24/01/2024 [14:07]
-		// var s string
-		// fmt.Scan(&s)
-
-		// return res.Success(String{
-		// 	Value: s,
-		// })
-
-		for i := 0; i < len(ctx.Symbol_Table.Get("value").(List).Elements); i++ {
-			switch rawArgs := rawArgs[i].(type) {
-			case VarAccessNode:
-				var s string
-				fmt.Scan(&s)
-
-				if parseFloat, err := strconv.ParseFloat(s, 64); err == nil {
-					ctx.Symbol_Table.Set(rawArgs.VarNameTok.Value, Number{Value: parseFloat, Context: ctx})
-					continue
-				}
-
-				ctx.Symbol_Table.Set(rawArgs.VarNameTok.Value, String{Value: s})
-			default:
-				return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, "Parameter must be a Variable access node", ctx))
-			}
-		}
-
-		return res.Success(Null{Context: ctx})
-	}
-}
-
+// Struct is a value of a struct type. Urutan lists the field names in the
+// order they are declared, which is the order a struct is printed in.
 type Struct struct {
+	Nama      string
 	Fields    map[string]Value
+	Urutan    []string
 	Context   *Context
 	Pos_Start *tools.Position
 	Pos_End   *tools.Position
+	dibagi    *bool
 }
 
 func (n Struct) Print() string {
@@ -846,6 +1005,7 @@ func (n Struct) Get_context() *Context {
 }
 
 func (n Struct) Copy() Value {
+	tandaiDibagi(n.dibagi)
 	return n
 }
 
@@ -892,45 +1052,6 @@ func (n Type) Is_true() bool {
 	return true
 }
 
-func (n BuiltInFunction) ExecuteIsNumber() ([]string, func(*Context, []Expr) Value) {
-	return []string{"value"}, func(ctx *Context, rawArgs []Expr) Value {
-		res := &RTResult{}
-		_, apakahNumber := ctx.Symbol_Table.Get("value").(Number)
-		if apakahNumber {
-			return res.Success(Number{Value: 1})
-		}
-		return res.Success(Number{Value: 0})
-	}
-}
-
-func (n BuiltInFunction) ExecuteIsString() ([]string, func(*Context, []Expr) Value) {
-	return []string{"value"}, func(ctx *Context, rawArgs []Expr) Value {
-		res := &RTResult{}
-		_, apakahString := ctx.Symbol_Table.Get("value").(String)
-		if apakahString {
-			return res.Success(Number{Value: 1})
-		}
-		return res.Success(Number{Value: 0})
-	}
-}
-
-func (n BuiltInFunction) ExecuteAppend() ([]string, func(*Context, []Expr) Value) {
-	return []string{"list", "value"}, func(ctx *Context, rawArgs []Expr) Value {
-		res := &RTResult{}
-
-		list, apakahList := ctx.Symbol_Table.Get("list").(List)
-		value := ctx.Symbol_Table.Get("value")
-
-		if !apakahList {
-			return res.Failure(RTError(*n.Pos_Start, *n.Pos_End, "First Argument must be list", ctx))
-		}
-
-		list.Elements = append(list.Elements, value)
-		ctx.Symbol_Table.Set("list", list)
-		return res.Success(Number{Value: 0})
-	}
-}
-
 type RTResult struct {
 	Value              Value
 	FuncReturnValue    Value
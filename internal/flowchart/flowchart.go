@@ -0,0 +1,355 @@
+package flowchart
+
+import (
+	"dap/internal/common"
+	"dap/tools"
+	"fmt"
+	"strings"
+)
+
+type Bentuk int
+
+const (
+	Terminal Bentuk = iota
+	Process
+	Decision
+	IO
+)
+
+type Node struct {
+	Id     string
+	Bentuk Bentuk
+	Label  string
+}
+
+type Edge struct {
+	From  string
+	To    string
+	Label string
+}
+
+// Chart is a flowchart built from the AST of a program or a single function.
+type Chart struct {
+	Name  string
+	Nodes []Node
+	Edges []Edge
+}
+
+// sambungan is a dangling exit of the chart built so far, waiting for the
+// next node to be connected to it (label is "yes"/"no" out of decisions).
+type sambungan struct {
+	from  string
+	label string
+}
+
+type loop struct {
+	continueTo string
+	breaks     []sambungan
+}
+
+type builder struct {
+	chart *Chart
+	end   string
+	loops []*loop
+}
+
+// FromProgram charts the algorithm section of a parsed program. Function
+// definitions are left out; chart them separately with FromFunction.
+func FromProgram(node common.Expr, programName string) *Chart {
+	b := &builder{chart: &Chart{Name: programName}}
+	start := b.tambah(Terminal, "start")
+	b.end = "end"
+
+	statements := make([]common.Expr, 0)
+	for _, v := range flatten(node) {
+		switch v.(type) {
+		case common.DictionaryNode, common.FuncNode, common.NullNode:
+			continue
+		}
+		statements = append(statements, v)
+	}
+
+	exits := b.blok(statements, []sambungan{{from: start}})
+	b.selesai("end", exits)
+	return b.chart
+}
+
+// FromFunction charts the body of the named function, or returns nil when the
+// program doesn't define it.
+func FromFunction(node common.Expr, functionName string) *Chart {
+	var target *common.FuncNode
+	walkFunctions(node, func(fn common.FuncNode) {
+		if fn.VarNameTok != nil && fn.VarNameTok.Value == functionName && target == nil {
+			target = &fn
+		}
+	})
+
+	if target == nil {
+		return nil
+	}
+
+	args := make([]string, 0, len(target.ArgNameToks))
+	for _, arg := range target.ArgNameToks {
+		args = append(args, arg.Value)
+	}
+
+	b := &builder{chart: &Chart{Name: functionName}}
+	start := b.tambah(Terminal, fmt.Sprintf("%s(%s)", functionName, strings.Join(args, ", ")))
+	b.end = "end"
+
+	var exits []sambungan
+	if target.ShouldAutoReturn {
+		id := b.tambah(Process, "return "+Teks(target.BodyNode))
+		b.sambung([]sambungan{{from: start}}, id)
+		b.chart.Edges = append(b.chart.Edges, Edge{From: id, To: b.end})
+	} else {
+		exits = b.blok(flatten(target.BodyNode), []sambungan{{from: start}})
+	}
+
+	b.selesai("end", exits)
+	return b.chart
+}
+
+func walkFunctions(node common.Expr, visit func(common.FuncNode)) {
+	for _, v := range flatten(node) {
+		if fn, ok := v.(common.FuncNode); ok {
+			visit(fn)
+		}
+	}
+}
+
+func flatten(node common.Expr) []common.Expr {
+	switch n := node.(type) {
+	case nil:
+		return nil
+	case *common.ParseResult:
+		return flatten(n.Node)
+	case common.ListNode:
+		hasil := make([]common.Expr, 0, len(n.ElementNode))
+		for _, v := range n.ElementNode {
+			if v != nil {
+				hasil = append(hasil, v)
+			}
+		}
+		return hasil
+	}
+
+	return []common.Expr{node}
+}
+
+func (b *builder) tambah(bentuk Bentuk, label string) string {
+	id := fmt.Sprintf("n%d", len(b.chart.Nodes))
+	b.chart.Nodes = append(b.chart.Nodes, Node{Id: id, Bentuk: bentuk, Label: label})
+	return id
+}
+
+func (b *builder) sambung(dari []sambungan, ke string) {
+	for _, v := range dari {
+		b.chart.Edges = append(b.chart.Edges, Edge{From: v.from, To: ke, Label: v.label})
+	}
+}
+
+// selesai adds the closing terminal and points the remaining exits and every
+// return at it.
+func (b *builder) selesai(label string, exits []sambungan) {
+	end := b.tambah(Terminal, label)
+	b.sambung(exits, end)
+
+	for i := range b.chart.Edges {
+		if b.chart.Edges[i].To == b.end {
+			b.chart.Edges[i].To = end
+		}
+	}
+}
+
+func (b *builder) blok(statements []common.Expr, masuk []sambungan) []sambungan {
+	for _, statement := range statements {
+		masuk = b.statement(statement, masuk)
+	}
+	return masuk
+}
+
+func (b *builder) statement(node common.Expr, masuk []sambungan) []sambungan {
+	switch n := node.(type) {
+	case nil, common.NullNode:
+		return masuk
+	case *common.ParseResult:
+		return b.statement(n.Node, masuk)
+	case common.ListNode:
+		return b.blok(flatten(n), masuk)
+	case common.CallNode:
+		bentuk := Process
+		if callee, ok := n.NodeToCall.(common.VarAccessNode); ok {
+			switch tools.SemuaBuiltInFunction[callee.VarNameTok.Value] {
+			case "Print", "Write", "Input", "ReadLn":
+				bentuk = IO
+			}
+		}
+		id := b.tambah(bentuk, Teks(n))
+		b.sambung(masuk, id)
+		return []sambungan{{from: id}}
+	case common.IfNode:
+		keluar := make([]sambungan, 0)
+		for _, kasus := range n.Cases {
+			id := b.tambah(Decision, Teks(kasus.Kondisi))
+			b.sambung(masuk, id)
+			keluar = append(keluar, b.blok(flatten(kasus.Isi), []sambungan{{from: id, label: "yes"}})...)
+			masuk = []sambungan{{from: id, label: "no"}}
+		}
+		if n.Else_case != nil && n.Else_case.Isi != nil {
+			masuk = b.blok(flatten(n.Else_case.Isi), masuk)
+		}
+		return append(keluar, masuk...)
+	case common.CaseNode:
+		keluar := make([]sambungan, 0)
+		subjek := Teks(n.SubjectNode)
+		for _, cabang := range n.Branches {
+			labels := make([]string, 0, len(cabang.Labels))
+			for _, label := range cabang.Labels {
+				labels = append(labels, label.Print())
+			}
+			id := b.tambah(Decision, fmt.Sprintf("%s is %s?", subjek, strings.Join(labels, ", ")))
+			b.sambung(masuk, id)
+			keluar = append(keluar, b.blok(flatten(cabang.Isi), []sambungan{{from: id, label: "yes"}})...)
+			masuk = []sambungan{{from: id, label: "no"}}
+		}
+		if n.Otherwise != nil {
+			masuk = b.blok(flatten(n.Otherwise), masuk)
+		}
+		return append(keluar, masuk...)
+	case common.WhileNode:
+		id := b.tambah(Decision, Teks(n.KondisiNode))
+		b.sambung(masuk, id)
+
+		l := &loop{continueTo: id}
+		b.loops = append(b.loops, l)
+		body := b.blok(flatten(n.BodyNode), []sambungan{{from: id, label: "yes"}})
+		b.loops = b.loops[:len(b.loops)-1]
+
+		b.sambung(body, id)
+		return append([]sambungan{{from: id, label: "no"}}, l.breaks...)
+	case common.ForEachNode:
+		elemen := n.VarNameTok.Value
+		if n.IndexTok != nil {
+			elemen = n.IndexTok.Value + ", " + elemen
+		}
+		id := b.tambah(Decision, fmt.Sprintf("next %s in %s?", elemen, Teks(n.IterableNode)))
+		b.sambung(masuk, id)
+
+		l := &loop{continueTo: id}
+		b.loops = append(b.loops, l)
+		body := b.blok(flatten(n.BodyNode), []sambungan{{from: id, label: "yes"}})
+		b.loops = b.loops[:len(b.loops)-1]
+
+		b.sambung(body, id)
+		return append([]sambungan{{from: id, label: "no"}}, l.breaks...)
+	case common.ForNode:
+		step := "1"
+		if _, ok := n.StepValueNode.(common.NullNode); !ok && n.StepValueNode != nil {
+			step = Teks(n.StepValueNode)
+		}
+		if n.Downto {
+			step = "-" + step
+		}
+		counter := n.VarNameTok.Value
+		comparison := "<="
+		if strings.HasPrefix(step, "-") {
+			comparison = ">="
+		}
+
+		init := b.tambah(Process, fmt.Sprintf("%s <- %s", counter, Teks(n.StartValueNode)))
+		b.sambung(masuk, init)
+		id := b.tambah(Decision, fmt.Sprintf("%s %s %s", counter, comparison, Teks(n.EndValueNode)))
+		b.sambung([]sambungan{{from: init}}, id)
+
+		l := &loop{}
+		b.loops = append(b.loops, l)
+		body := b.blok(flatten(n.BodyNode), []sambungan{{from: id, label: "yes"}})
+		b.loops = b.loops[:len(b.loops)-1]
+
+		var increment string
+		if strings.HasPrefix(step, "-") {
+			increment = b.tambah(Process, fmt.Sprintf("%s <- %s - %s", counter, counter, strings.TrimPrefix(step, "-")))
+		} else {
+			increment = b.tambah(Process, fmt.Sprintf("%s <- %s + %s", counter, counter, step))
+		}
+		b.sambung(body, increment)
+		b.arahkanContinue(l, increment)
+		b.sambung([]sambungan{{from: increment}}, id)
+		return append([]sambungan{{from: id, label: "no"}}, l.breaks...)
+	case common.RepeatNode:
+		pertama := len(b.chart.Nodes)
+		edgeAwal := len(b.chart.Edges)
+
+		l := &loop{}
+		b.loops = append(b.loops, l)
+		body := b.blok(flatten(n.BodyNode), masuk)
+		b.loops = b.loops[:len(b.loops)-1]
+
+		id := b.tambah(Decision, Teks(n.KondisiNode))
+		b.sambung(body, id)
+		b.arahkanContinue(l, id)
+
+		// Loop back to whatever the incoming edges were connected to first.
+		awal := id
+		if len(b.chart.Nodes) > pertama+1 && edgeAwal < len(b.chart.Edges) {
+			awal = b.chart.Edges[edgeAwal].To
+		}
+		b.chart.Edges = append(b.chart.Edges, Edge{From: id, To: awal, Label: "no"})
+		return append([]sambungan{{from: id, label: "yes"}}, l.breaks...)
+	case common.ReturnNode:
+		label := "return"
+		if n.NodeToReturn != nil {
+			label += " " + Teks(n.NodeToReturn)
+		}
+		id := b.tambah(Process, label)
+		b.sambung(masuk, id)
+		b.chart.Edges = append(b.chart.Edges, Edge{From: id, To: b.end})
+		return nil
+	case common.BreakNode:
+		if len(b.loops) == 0 {
+			return masuk
+		}
+		l := b.loops[len(b.loops)-1]
+		l.breaks = append(l.breaks, masuk...)
+		return nil
+	case common.ContinueNode:
+		if len(b.loops) == 0 {
+			return masuk
+		}
+		l := b.loops[len(b.loops)-1]
+		if l.continueTo != "" {
+			b.sambung(masuk, l.continueTo)
+		} else {
+			l.breaks = append(l.breaks, sambungan{from: "continue"})
+			for _, v := range masuk {
+				b.chart.Edges = append(b.chart.Edges, Edge{From: v.from, To: "continue", Label: v.label})
+			}
+		}
+		return nil
+	case common.FuncNode:
+		return masuk
+	}
+
+	id := b.tambah(Process, Teks(node))
+	b.sambung(masuk, id)
+	return []sambungan{{from: id}}
+}
+
+// arahkanContinue points the continue edges of a for/repeat loop, which were
+// left dangling while the body was built, at the node that ends an iteration.
+func (b *builder) arahkanContinue(l *loop, ke string) {
+	breaks := make([]sambungan, 0, len(l.breaks))
+	for _, v := range l.breaks {
+		if v.from != "continue" {
+			breaks = append(breaks, v)
+		}
+	}
+	l.breaks = breaks
+
+	for i := range b.chart.Edges {
+		if b.chart.Edges[i].To == "continue" {
+			b.chart.Edges[i].To = ke
+		}
+	}
+}
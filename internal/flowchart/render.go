@@ -0,0 +1,65 @@
+package flowchart
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (c *Chart) Dot() string {
+	var hasil strings.Builder
+
+	fmt.Fprintf(&hasil, "digraph %q {\n", c.Name)
+	hasil.WriteString("    node [fontname=\"Helvetica\"];\n")
+	for _, node := range c.Nodes {
+		shape := "box"
+		switch node.Bentuk {
+		case Terminal:
+			shape = "oval"
+		case Decision:
+			shape = "diamond"
+		case IO:
+			shape = "parallelogram"
+		}
+		fmt.Fprintf(&hasil, "    %s [shape=%s, label=%q];\n", node.Id, shape, node.Label)
+	}
+
+	for _, edge := range c.Edges {
+		if edge.Label != "" {
+			fmt.Fprintf(&hasil, "    %s -> %s [label=%q];\n", edge.From, edge.To, edge.Label)
+		} else {
+			fmt.Fprintf(&hasil, "    %s -> %s;\n", edge.From, edge.To)
+		}
+	}
+	hasil.WriteString("}\n")
+
+	return hasil.String()
+}
+
+func (c *Chart) Mermaid() string {
+	var hasil strings.Builder
+
+	hasil.WriteString("flowchart TD\n")
+	for _, node := range c.Nodes {
+		label := `"` + strings.ReplaceAll(node.Label, `"`, "#quot;") + `"`
+		switch node.Bentuk {
+		case Terminal:
+			fmt.Fprintf(&hasil, "    %s([%s])\n", node.Id, label)
+		case Decision:
+			fmt.Fprintf(&hasil, "    %s{%s}\n", node.Id, label)
+		case IO:
+			fmt.Fprintf(&hasil, "    %s[/%s/]\n", node.Id, label)
+		default:
+			fmt.Fprintf(&hasil, "    %s[%s]\n", node.Id, label)
+		}
+	}
+
+	for _, edge := range c.Edges {
+		if edge.Label != "" {
+			fmt.Fprintf(&hasil, "    %s -->|%s| %s\n", edge.From, edge.Label, edge.To)
+		} else {
+			fmt.Fprintf(&hasil, "    %s --> %s\n", edge.From, edge.To)
+		}
+	}
+
+	return hasil.String()
+}
@@ -0,0 +1,83 @@
+package flowchart
+
+import (
+	"dap/internal/common"
+	"fmt"
+	"strings"
+)
+
+// Teks prints an expression back roughly the way it was written in DAP, for
+// the labels of the flowchart boxes.
+func Teks(node common.Expr) string {
+	switch n := node.(type) {
+	case nil:
+		return ""
+	case *common.ParseResult:
+		return Teks(n.Node)
+	case common.NumberNode:
+		return n.Token.Value
+	case common.StringNode:
+		return n.Token.Value
+	case common.NullNode:
+		return "null"
+	case common.VarAccessNode:
+		return n.VarNameTok.Value
+	case common.VarAssignNode:
+		return fmt.Sprintf("%s <- %s", n.VarName.Value, Teks(n.ValueNode))
+	case common.ArrayIndexNode:
+		if n.Dimension > 1 {
+			return fmt.Sprintf("%s, %s]", strings.TrimSuffix(Teks(n.Left), "]"), Teks(n.Index))
+		}
+		return fmt.Sprintf("%s[%s]", Teks(n.Left), Teks(n.Index))
+	case common.SliceNode:
+		return fmt.Sprintf("%s[%s..%s]", Teks(n.Left), Teks(n.Low), Teks(n.High))
+	case common.StructLiteralNode:
+		return n.Print()
+	case common.ArrayAssignNode:
+		return fmt.Sprintf("%s <- %s", Teks(n.ArrayAccess), Teks(n.ValueNode))
+	case common.MemberAccessNode:
+		return fmt.Sprintf("%s.%s", Teks(n.Object), n.MemberTok.Value)
+	case common.MemberAssignNode:
+		return fmt.Sprintf("%s <- %s", Teks(n.MemberAccess), Teks(n.ValueNode))
+	case common.DerefNode:
+		return Teks(n.Pointer) + "^"
+	case common.DerefAssignNode:
+		return fmt.Sprintf("%s <- %s", Teks(n.Deref), Teks(n.ValueNode))
+	case common.FormatNode:
+		if n.Precision != nil {
+			return fmt.Sprintf("%s:%s:%s", Teks(n.Value), Teks(n.Width), Teks(n.Precision))
+		}
+		return fmt.Sprintf("%s:%s", Teks(n.Value), Teks(n.Width))
+	case common.UnaryOpNode:
+		return n.Operator.Value + kurung(n.Node)
+	case common.BinOpNode:
+		return fmt.Sprintf("%s %s %s", kurung(n.Left), n.Operator.Value, kurung(n.Right))
+	case common.CallNode:
+		args := make([]string, 0, len(n.ArgNodes))
+		for _, arg := range n.ArgNodes {
+			args = append(args, Teks(arg))
+		}
+		return fmt.Sprintf("%s(%s)", Teks(n.NodeToCall), strings.Join(args, ", "))
+	case common.ListNode:
+		elements := make([]string, 0, len(n.ElementNode))
+		for _, v := range n.ElementNode {
+			elements = append(elements, Teks(v))
+		}
+		return strings.Join(elements, "; ")
+	case common.ReturnNode:
+		return strings.TrimSpace("return " + Teks(n.NodeToReturn))
+	case common.BreakNode:
+		return "break"
+	case common.ContinueNode:
+		return "continue"
+	}
+
+	return node.Name()
+}
+
+func kurung(node common.Expr) string {
+	if _, ok := node.(common.BinOpNode); ok {
+		return "(" + Teks(node) + ")"
+	}
+	return Teks(node)
+}
@@ -5,11 +5,19 @@ import (
 	"dap/internal/lexer"
 	"dap/tools"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 )
 
-type Interpreter struct{}
+type Interpreter struct {
+	Trace *Trace
+	// ForKetat makes the variable of a for loop read-only inside its body.
+	ForKetat bool
+	// panggilanSaja is set while a block runs a call as a statement of its
+	// own, where nothing evaluated before it can still be looking.
+	panggilanSaja bool
+}
 
 func (i *Interpreter) Visit(node common.Expr, context *common.Context) common.Value {
 	methodName := "Visit" + reflect.TypeOf(node).Name()
@@ -56,7 +64,13 @@ func (i *Interpreter) VisitNumberNode(node common.Expr, context *common.Context)
 func (i *Interpreter) VisitStringNode(node common.Expr, context *common.Context) common.Value {
 	nodeToken := node.(common.StringNode).Token
 	res := &common.RTResult{}
-	return res.Success(common.String{Value: nodeToken.Value[1 : len(nodeToken.Value)-1], Context: context}.Set_pos(nodeToken.Pos_Start, nodeToken.Pos_End))
+
+	value, err := tools.UnescapeString(nodeToken.Value[1 : len(nodeToken.Value)-1])
+	if err != nil {
+		return res.Failure(common.RTError(*nodeToken.Pos_Start, *nodeToken.Pos_End, err.Error(), context))
+	}
+
+	return res.Success(common.String{Value: value, Context: context}.Set_pos(nodeToken.Pos_Start, nodeToken.Pos_End))
 }
 
 func (i *Interpreter) VisitDictionaryNode(node common.Expr, context *common.Context) common.Value {
@@ -68,6 +82,10 @@ func (i *Interpreter) VisitDictionaryNode(node common.Expr, context *common.Cont
 		if res.ShouldReturn() {
 			return res
 		}
+
+		if declaration, ok := v.(common.VarAssignNode); ok && !declaration.ApakahConst {
+			context.Symbol_Table.Set(tipeVariabel+declaration.VarName.Value, common.Type{Definition: declaration.ValueNode, Context: context})
+		}
 	}
 
 	return res.Success(common.Null{})
@@ -79,10 +97,21 @@ func (i *Interpreter) VisitListNode(node common.Expr, context *common.Context) c
 	elements := make([]common.Value, 0)
 
 	for _, v := range nodeList.ElementNode {
-		elements = append(elements, res.Register(i.Visit(v, context)))
+		_, i.panggilanSaja = v.(common.CallNode)
+		i.panggilanSaja = i.panggilanSaja && nodeList.Blok
+		element := res.Register(i.Visit(v, context))
 		if res.ShouldReturn() {
 			return res
 		}
+		if element != nil {
+			element = element.Copy()
+		}
+		elements = append(elements, element)
+
+		// Elements of a list literal aren't statements of their own.
+		if i.Trace != nil && nodeList.Blok {
+			i.Trace.Catat(v)
+		}
 	}
 
 	Listvalue := common.List{
@@ -90,7 +119,7 @@ func (i *Interpreter) VisitListNode(node common.Expr, context *common.Context) c
 	}
 	Listvalue.Set_pos(nodeList.Pos_Start, nodeList.Pos_End)
 	Listvalue.Set_context(context)
-	return res.Success(Listvalue)
+	return res.Success(common.MilikSendiri(Listvalue))
 }
 
 func (i *Interpreter) VisitVarAccessNode(node common.Expr, context *common.Context) common.Value {
@@ -108,7 +137,7 @@ func (i *Interpreter) VisitVarAccessNode(node common.Expr, context *common.Conte
 		return res.Failure(common.RTError(*node.GetPosStart(), *node.GetPosEnd(), fmt.Sprintf("'%s' is not defined", var_name), context))
 	}
 
-	value = value.Copy().Set_pos(nodeVarAccessNode.Pos_Start, nodeVarAccessNode.Pos_end).Set_context(context)
+	value = value.Set_pos(nodeVarAccessNode.Pos_Start, nodeVarAccessNode.Pos_end).Set_context(context)
 
 	return res.Success(value)
 }
@@ -132,7 +161,7 @@ func (i *Interpreter) VisitVarAssignNode(node common.Expr, context *common.Conte
 		}
 	}
 
-	res.Register(i.GantiVariable(nodeVarAssignNode.VarName.Value, value, context, nodeVarAssignNode.ApakahConst, nodeVarAssignNode.Pos_Start.Copy(), nodeVarAssignNode.Pos_end.Copy()))
+	res.Register(i.GantiVariable(nodeVarAssignNode.VarName.Value, value.Copy(), context, nodeVarAssignNode.ApakahConst, nodeVarAssignNode.Pos_Start.Copy(), nodeVarAssignNode.Pos_end.Copy()))
 	if res.Error != nil {
 		return res
 	}
@@ -194,6 +223,33 @@ func (i *Interpreter) VisitBinOpNode(node common.Expr, context *common.Context)
 			hasil, err = left.Added_to(right)
 		case lexer.STAR:
 			hasil, err = left.Multed_by(right)
+		case lexer.EQUALS:
+			hasil, err = left.Get_comparison_eq(right)
+		case lexer.NOT_EQUALS:
+			hasil, err = left.Get_comparison_nq(right)
+		case lexer.LESS:
+			hasil, err = left.Get_comparison_lt(right)
+		case lexer.LESS_EQUALS:
+			hasil, err = left.Get_comparison_lte(right)
+		case lexer.GREATER:
+			hasil, err = left.Get_comparison_gt(right)
+		case lexer.GREATER_EQUALS:
+			hasil, err = left.Get_comparison_gte(right)
+		}
+	case common.Enum:
+		switch nodeBinary.Operator.Kind {
+		case lexer.EQUALS:
+			hasil, err = left.Get_comparison_eq(right)
+		case lexer.NOT_EQUALS:
+			hasil, err = left.Get_comparison_nq(right)
+		case lexer.LESS:
+			hasil, err = left.Get_comparison_lt(right)
+		case lexer.LESS_EQUALS:
+			hasil, err = left.Get_comparison_lte(right)
+		case lexer.GREATER:
+			hasil, err = left.Get_comparison_gt(right)
+		case lexer.GREATER_EQUALS:
+			hasil, err = left.Get_comparison_gte(right)
 		}
 	case common.List:
 		switch nodeBinary.Operator.Kind {
@@ -208,10 +264,29 @@ func (i *Interpreter) VisitBinOpNode(node common.Expr, context *common.Context)
 		}
 	}
 
+	switch left.(type) {
+	case common.Array, common.List, common.Struct, common.Null, common.Pointer:
+		switch nodeBinary.Operator.Kind {
+		case lexer.EQUALS, lexer.NOT_EQUALS:
+			if common.NamaTipe(left) != common.NamaTipe(right) {
+				break
+			}
+			sama := common.ApakahSama(left, right)
+			if nodeBinary.Operator.Kind == lexer.NOT_EQUALS {
+				sama = !sama
+			}
+			hasil = common.Number{Value: float64(tools.GetComparison(sama)), Context: context}
+		}
+	}
+
 	if err != nil {
 		return res.Failure(*err)
 	}
 
+	if _, ok := hasil.(common.Null); ok {
+		return res.Failure(common.RTError(*node.GetPosStart(), *node.GetPosEnd(), fmt.Sprintf("Illegal operation: cannot use '%s' between %s and %s", nodeBinary.Operator.Value, common.NamaTipe(left), common.NamaTipe(right)), context))
+	}
+
 	return res.Success(hasil.Set_pos(nodeBinary.Pos_Start, nodeBinary.Pos_End))
 }
 
@@ -224,6 +299,13 @@ func (i *Interpreter) VisitUnaryOpNode(node common.Expr, context *common.Context
 		return res
 	}
 
+	switch nodeUnary.Operator.Kind {
+	case lexer.DASH, lexer.NOT:
+		if _, ok := number.(common.Number); !ok {
+			return res.Failure(common.RTError(*nodeUnary.Operator.Pos_Start, *nodeUnary.Node.GetPosEnd(), fmt.Sprintf("Illegal operation: cannot use '%s' on %s", nodeUnary.Operator.Value, common.NamaTipe(number)), context))
+		}
+	}
+
 	var error *common.Error
 	switch nodeUnary.Operator.Kind {
 	case lexer.DASH:
@@ -282,55 +364,92 @@ func (i *Interpreter) VisitIfNode(node common.Expr, context *common.Context) com
 	return res.Success(common.Null{})
 }
 
+// VisitForNode runs a for loop. The bounds and the step are numbers, reals
+// included, evaluated once before the first round; the loop variable takes
+// start + k*step for k = 0, 1, ... until it passes the end, so changing it in
+// the body doesn't change how often the loop runs. With ForKetat the body
+// can't assign the loop variable at all.
 func (i *Interpreter) VisitForNode(node common.Expr, context *common.Context) common.Value {
 	res := &common.RTResult{}
 	elements := make([]common.Value, 0)
 
 	nodeFor := node.(common.ForNode)
-	startValue := res.Register(i.Visit(nodeFor.StartValueNode, context)).(common.Number)
+	startValue := res.Register(i.angkaFor(nodeFor.StartValueNode, "start", context))
 	if res.ShouldReturn() {
 		return res
 	}
 
-	endValue := res.Register(i.Visit(nodeFor.EndValueNode, context)).(common.Number)
+	endValue := res.Register(i.angkaFor(nodeFor.EndValueNode, "end", context))
 	if res.ShouldReturn() {
 		return res
 	}
 
-	var stepValue common.Number
+	step := 1.0
 	switch nodeFor.StepValueNode.(type) {
 	case common.NullNode:
-		stepValue = common.Number{Value: 1}
 	default:
-		stepValue = res.Register(i.Visit(nodeFor.StepValueNode, context)).(common.Number)
-
+		stepValue := res.Register(i.angkaFor(nodeFor.StepValueNode, "step", context))
 		if res.ShouldReturn() {
 			return res
 		}
+		step = stepValue.(common.Number).Value
+
+		if step == 0 {
+			return res.Failure(common.RTError(*nodeFor.StepValueNode.GetPosStart(), *nodeFor.StepValueNode.GetPosEnd(), "The step of a for loop can't be 0", context))
+		}
+		if nodeFor.Downto && step < 0 {
+			return res.Failure(common.RTError(*nodeFor.StepValueNode.GetPosStart(), *nodeFor.StepValueNode.GetPosEnd(), "The step of a downto loop is how much it counts down by, so it must be positive", context))
+		}
+	}
+	if nodeFor.Downto {
+		step = -step
 	}
 
-	iteration := int(startValue.Value)
+	// A loop over an enumeration counts through the ordinals of its values.
+	awalEnum, apakahEnum := startValue.(common.Enum)
+	akhirEnum, akhirApakahEnum := endValue.(common.Enum)
+	if apakahEnum != akhirApakahEnum || (apakahEnum && awalEnum.Tipe != akhirEnum.Tipe) {
+		return res.Failure(common.RTError(*nodeFor.EndValueNode.GetPosStart(), *nodeFor.EndValueNode.GetPosEnd(), fmt.Sprintf("The end of a for loop from a %s must be a %s too, not a %s", common.NamaTipe(startValue), common.NamaTipe(startValue), common.NamaTipe(endValue)), context))
+	}
+	if apakahEnum && step != math.Trunc(step) {
+		return res.Failure(common.RTError(*nodeFor.StepValueNode.GetPosStart(), *nodeFor.StepValueNode.GetPosEnd(), fmt.Sprintf("The step of a for loop over %s must be a whole number", awalEnum.Tipe), context))
+	}
 
-	var kondisi func(iteration int, endValue int) bool
-	if stepValue.Value >= 0 {
-		kondisi = func(iteration, endValue int) bool {
-			return iteration <= endValue
-		}
+	var start, end float64
+	if apakahEnum {
+		start, end = float64(awalEnum.Ordinal), float64(akhirEnum.Ordinal)
 	} else {
-		kondisi = func(iteration, endValue int) bool {
-			return iteration >= endValue
+		start, end = startValue.(common.Number).Value, endValue.(common.Number).Value
+	}
+	varName := nodeFor.VarNameTok.Value
+	tandaLoop := "ApakahLoop " + varName
+
+	if i.ForKetat {
+		if _, ok := context.Symbol_Table.Symbols[tandaLoop]; ok {
+			return res.Failure(common.RTError(*nodeFor.VarNameTok.Pos_Start, *nodeFor.VarNameTok.Pos_End, fmt.Sprintf("'%s' is already the variable of an enclosing for loop", varName), context))
 		}
+		defer context.Symbol_Table.Remove(tandaLoop)
 	}
 
-	for kondisi(iteration, int(endValue.Value)) {
-		res.Register(i.GantiVariable(nodeFor.VarNameTok.Value, common.Number{Value: float64(iteration)}, context, false, nodeFor.VarNameTok.Pos_Start.Copy(), nodeFor.VarNameTok.Pos_End.Copy()))
-		if res.Error != nil {
-			return res
+	for k := 0; ; k++ {
+		iteration := nilaiFor(start, step, k)
+		if (step > 0 && iteration > end) || (step < 0 && iteration < end) {
+			break
 		}
 
-		// context.Symbol_Table.Set(nodeFor.VarNameTok.Value, common.Number{Value: float64(iteration)})
+		var nilai common.Value = common.Number{Value: iteration}
+		if apakahEnum {
+			nilai, _ = awalEnum.Ke(int(iteration))
+		}
 
-		iteration += int(stepValue.Value)
+		context.Symbol_Table.Remove(tandaLoop)
+		res.Register(i.GantiVariable(varName, nilai, context, false, nodeFor.VarNameTok.Pos_Start.Copy(), nodeFor.VarNameTok.Pos_End.Copy()))
+		if res.Error != nil {
+			return res
+		}
+		if i.ForKetat {
+			context.Symbol_Table.Set(tandaLoop, common.Number{Value: 1})
+		}
 
 		value := res.Register(i.Visit(nodeFor.BodyNode, context))
 		if res.ShouldReturn() && !res.LoopShouldContinue && !res.LoopShouldBreak {
@@ -361,6 +480,177 @@ func (i *Interpreter) VisitForNode(node common.Expr, context *common.Context) co
 	return res.Success(ListValue)
 }
 
+// angkaFor evaluates a bound or the step of a for loop, which must be a number.
+// A bound may also be a value of an enumeration.
+func (i *Interpreter) angkaFor(node common.Expr, bagian string, context *common.Context) common.Value {
+	res := &common.RTResult{}
+	value := res.Register(i.Visit(node, context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	if _, ok := value.(common.Enum); ok && bagian != "step" {
+		return res.Success(value)
+	}
+	if _, ok := value.(common.Number); !ok {
+		return res.Failure(common.RTError(*node.GetPosStart(), *node.GetPosEnd(), fmt.Sprintf("The %s of a for loop must be a number, not a %s", bagian, common.NamaTipe(value)), context))
+	}
+	return res.Success(value)
+}
+
+// nilaiFor is the k-th value of a loop variable. Rounding to 15 digits keeps
+// a real step from drifting, so 0 to 0.3 step 0.1 stops at 0.3, not before it.
+func nilaiFor(start, step float64, k int) float64 {
+	nilai := start + float64(k)*step
+	if nilai == math.Trunc(nilai) {
+		return nilai
+	}
+	bulat, _ := strconv.ParseFloat(strconv.FormatFloat(nilai, 'g', 15, 64), 64)
+	return bulat
+}
+
+// VisitForEachNode runs the body once for every element of an array, a list or
+// a string, in order. The collection is taken as it was when the loop started,
+// and x gets a copy of each element, so changing x leaves the collection as
+// it is. The index of the indexed form follows the bounds of an array and
+// counts from 1 otherwise.
+func (i *Interpreter) VisitForEachNode(node common.Expr, context *common.Context) common.Value {
+	res := &common.RTResult{}
+	elements := make([]common.Value, 0)
+
+	nodeForEach := node.(common.ForEachNode)
+	collection := res.Register(i.Visit(nodeForEach.IterableNode, context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	var items []common.Value
+	start := 1
+	switch c := collection.(type) {
+	case common.Array:
+		items = append(items, c.Elements...)
+		start = c.Start
+	case common.List:
+		items = append(items, c.Elements...)
+	case common.String:
+		for _, r := range c.Value {
+			items = append(items, common.String{Value: string(r)})
+		}
+	default:
+		return res.Failure(common.RTError(*nodeForEach.IterableNode.GetPosStart(), *nodeForEach.IterableNode.GetPosEnd(), fmt.Sprintf("foreach needs an array, a list or a string, got %s", common.NamaTipe(collection)), context))
+	}
+
+	for idx, item := range items {
+		if nodeForEach.IndexTok != nil {
+			res.Register(i.GantiVariable(nodeForEach.IndexTok.Value, common.Number{Value: float64(start + idx)}, context, false, nodeForEach.IndexTok.Pos_Start.Copy(), nodeForEach.IndexTok.Pos_End.Copy()))
+			if res.Error != nil {
+				return res
+			}
+		}
+
+		res.Register(i.GantiVariable(nodeForEach.VarNameTok.Value, item.Copy().Set_context(context), context, false, nodeForEach.VarNameTok.Pos_Start.Copy(), nodeForEach.VarNameTok.Pos_End.Copy()))
+		if res.Error != nil {
+			return res
+		}
+
+		value := res.Register(i.Visit(nodeForEach.BodyNode, context))
+		if res.ShouldReturn() && !res.LoopShouldContinue && !res.LoopShouldBreak {
+			return res
+		}
+
+		if res.LoopShouldContinue {
+			continue
+		}
+
+		if res.LoopShouldBreak {
+			break
+		}
+
+		elements = append(elements, value)
+	}
+
+	if nodeForEach.ShouldReturnNull {
+		return res.Success(common.Null{})
+	}
+
+	ListValue := common.List{
+		Elements: elements,
+	}
+	ListValue.Set_pos(nodeForEach.Pos_Start, nodeForEach.Pos_end)
+	ListValue.Set_context(context)
+
+	return res.Success(ListValue)
+}
+
+// VisitCaseNode runs the first branch with a label matching the subject, or
+// otherwise when none does. A range matches the values between its ends,
+// both included. Nothing runs when no label matches and there's no otherwise.
+func (i *Interpreter) VisitCaseNode(node common.Expr, context *common.Context) common.Value {
+	res := &common.RTResult{}
+	nodeCase := node.(common.CaseNode)
+
+	subject := res.Register(i.Visit(nodeCase.SubjectNode, context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	for _, branch := range nodeCase.Branches {
+		for _, label := range branch.Labels {
+			cocok := res.Register(i.cocokLabel(subject, label, context))
+			if res.ShouldReturn() {
+				return res
+			}
+
+			if cocok.Is_true() {
+				return i.Visit(branch.Isi, context)
+			}
+		}
+	}
+
+	if nodeCase.Otherwise != nil {
+		return i.Visit(nodeCase.Otherwise, context)
+	}
+
+	return res.Success(common.Null{})
+}
+
+func (i *Interpreter) cocokLabel(subject common.Value, label common.CaseLabel, context *common.Context) common.Value {
+	res := &common.RTResult{}
+
+	low := res.Register(i.Visit(label.Low, context))
+	if res.ShouldReturn() {
+		return res
+	}
+	if label.High == nil {
+		return res.Success(common.Number{Value: float64(tools.GetComparison(common.ApakahSama(subject, low)))})
+	}
+
+	high := res.Register(i.Visit(label.High, context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	cocok := false
+	switch v := subject.(type) {
+	case common.Number:
+		if lo, ok := low.(common.Number); ok {
+			cocok = lo.Value <= v.Value && v.Value <= high.(common.Number).Value
+		}
+	case common.String:
+		if lo, ok := low.(common.String); ok {
+			cocok = lo.Value <= v.Value && v.Value <= high.(common.String).Value
+		}
+	case common.Enum:
+		lo, okLow := low.(common.Enum)
+		hi, okHigh := high.(common.Enum)
+		if okLow && okHigh && lo.Tipe == v.Tipe && hi.Tipe == v.Tipe {
+			cocok = lo.Ordinal <= v.Ordinal && v.Ordinal <= hi.Ordinal
+		}
+	}
+
+	return res.Success(common.Number{Value: float64(tools.GetComparison(cocok))})
+}
+
 func (i *Interpreter) VisitWhileNode(node common.Expr, context *common.Context) common.Value {
 	res := &common.RTResult{}
 	nodeWhile := node.(common.WhileNode)
@@ -496,6 +786,8 @@ func (i *Interpreter) VisitCallNode(node common.Expr, context *common.Context) c
 	args := make([]common.Value, 0)
 	rawArgs := make([]common.Expr, 0)
 	nodeCall := node.(common.CallNode)
+	sendiri := i.panggilanSaja
+	i.panggilanSaja = false
 
 	value_to_call := res.Register(i.Visit(nodeCall.NodeToCall, context))
 	if res.ShouldReturn() {
@@ -503,6 +795,14 @@ func (i *Interpreter) VisitCallNode(node common.Expr, context *common.Context) c
 	}
 	value_to_call = value_to_call.Copy().Set_pos(nodeCall.Pos_Start, nodeCall.Pos_end)
 
+	// read and new store into their arguments instead of taking their values.
+	if builtin, ok := value_to_call.(common.BuiltInFunction); ok && !builtin.Terdaftar && (builtin.Name == "Input" || builtin.Name == "ReadLn") {
+		return i.baca(nodeCall, builtin.Name == "ReadLn", context)
+	}
+	if builtin, ok := value_to_call.(common.BuiltInFunction); ok && !builtin.Terdaftar && builtin.Name == "New" {
+		return i.baru(nodeCall, context)
+	}
+
 	for _, argNode := range nodeCall.ArgNodes {
 		rawArgs = append(rawArgs, argNode)
 		args = append(args, res.Register(i.Visit(argNode, context)))
@@ -514,21 +814,63 @@ func (i *Interpreter) VisitCallNode(node common.Expr, context *common.Context) c
 	var returnValue common.Value
 	switch value_to_call := value_to_call.(type) {
 	case common.BuiltInFunction:
+		res.Register(i.cekTambahan(value_to_call, rawArgs, args, context))
+		if res.ShouldReturn() {
+			return res
+		}
 		returnValue = res.Register(value_to_call.Execute(args, rawArgs))
+		if res.ShouldReturn() {
+			return res
+		}
 		if returnValueContext := returnValue.Get_context(); returnValueContext != nil {
 			for _, v := range rawArgs {
 				switch v := v.(type) {
 				case common.VarAccessNode:
-					res.Register(i.GantiVariable(v.VarNameTok.Value, returnValueContext.Symbol_Table.Get(v.VarNameTok.Value), context, false, nil, nil))
+					// Only the variables the built-in set in its own scope changed.
+					value, ok := returnValueContext.Symbol_Table.Symbols[v.VarNameTok.Value]
+					if !ok {
+						continue
+					}
+					res.Register(i.GantiVariable(v.VarNameTok.Value, value, context, false, v.Pos_Start, v.Pos_end))
 					if res.Error != nil {
 						return res
 					}
 					// context.Symbol_Table.Set(v.VarNameTok.Value, returnValueContext.Symbol_Table.Get(v.VarNameTok.Value))
+				case common.ArrayIndexNode, common.MemberAccessNode, common.DerefNode:
+					value, ok := returnValueContext.Symbol_Table.Symbols[v.Print()]
+					if !ok {
+						continue
+					}
+					// The list a built-in hands back is held by nothing else, so
+					// a call on its own stores it as it is. Inside an
+					// expression an argument evaluated before, as b in
+					// writeln b, remove(b.items, 1), may still hold the
+					// variable; marking it shared keeps that one as it was.
+					if !sendiri {
+						if akar, ok := akarTempat(v); ok {
+							if lama := context.Symbol_Table.Get(akar.VarNameTok.Value); lama != nil {
+								lama.Copy()
+							}
+						}
+						value = value.Copy()
+					}
+					res.Register(i.cekNilai(i.tipeDeklarasi(v, context), value, v.Print(), v.GetPosStart(), v.GetPosEnd(), context))
+					if res.ShouldReturn() {
+						return res
+					}
+					res.Register(i.ubahTempat(v, func(common.Value) common.Value {
+						return value
+					}, v, context))
+					if res.Error != nil {
+						return res
+					}
 				}
 			}
 		}
-	default: //Normal Function
+	case common.Function:
 		returnValue = res.Register(i.Execute(value_to_call, context, args))
+	default:
+		return res.Failure(common.RTError(*node.GetPosStart(), *node.GetPosEnd(), fmt.Sprintf("'%s' is a %s, not a function", nodeCall.NodeToCall.Print(), common.NamaTipe(value_to_call)), context))
 	}
 	if res.ShouldReturn() {
 		return res
@@ -565,11 +907,16 @@ func (i *Interpreter) VisitBreakNode(node common.Expr, context *common.Context)
 
 func (i *Interpreter) Execute(node common.Value, context *common.Context, args []common.Value) common.Value {
 	res := &common.RTResult{}
-	inter := Interpreter{}
+	inter := i
 	nodeFunc := node.(common.BaseFunctionInterface)
 
 	exec_ctx := nodeFunc.GenerateNewContext()
 
+	// Parameters are passed by value.
+	for idx, arg := range args {
+		args[idx] = arg.Copy()
+	}
+
 	res.Register(nodeFunc.CheckAndPopulateArgs(nodeFunc.GetArgsName(), args, &exec_ctx))
 	if res.ShouldReturn() {
 		return res
@@ -608,125 +955,502 @@ func (i *Interpreter) GantiVariable(varName string, value common.Value, context
 		return res.Failure(common.RTError(*posStart, *posend, fmt.Sprintf("Constant variable '%s' can not be assigned!", varName), context))
 	}
 
+	if _, ok := context.Symbol_Table.Symbols["ApakahLoop "+varName]; ok {
+		return res.Failure(common.RTError(*posStart, *posend, fmt.Sprintf("'%s' is the variable of a for loop and can not be assigned inside it", varName), context))
+	}
+
+	if tipe, ok := context.Symbol_Table.Symbols[tipeVariabel+varName].(common.Type); ok {
+		res.Register(i.cekNilai(i.resolveTipe(tipe.Definition, context), value, varName, posStart, posend, context))
+		if res.ShouldReturn() {
+			return res
+		}
+	}
+
 	context.Symbol_Table.Set(varName, value)
 
 	return res.Success(common.Null{})
 }
 
+func (i *Interpreter) VisitFileTypeNode(node common.Expr, context *common.Context) common.Value {
+	return i.InitializeType(node, context)
+}
+
+func (i *Interpreter) VisitListTypeNode(node common.Expr, context *common.Context) common.Value {
+	return i.InitializeType(node, context)
+}
+
+func (i *Interpreter) VisitEnumTypeNode(node common.Expr, context *common.Context) common.Value {
+	return i.InitializeType(node, context)
+}
+
+func (i *Interpreter) VisitSubrangeTypeNode(node common.Expr, context *common.Context) common.Value {
+	return i.InitializeType(node, context)
+}
+
 func (i *Interpreter) VisitArrayTypeNode(node common.Expr, context *common.Context) common.Value {
+	return i.InitializeType(node, context)
+}
+
+func (i *Interpreter) VisitArrayIndexNode(node common.Expr, context *common.Context) common.Value {
 	res := &common.RTResult{}
-	arrayNode := node.(common.ArrayTypeNode)
+	indexNode := node.(common.ArrayIndexNode)
 
-	startVal := res.Register(i.Visit(arrayNode.StartNode, context))
+	left := res.Register(i.Visit(indexNode.Left, context))
 	if res.ShouldReturn() {
 		return res
 	}
 
-	endVal := res.Register(i.Visit(arrayNode.EndNode, context))
+	indexVal := res.Register(i.Visit(indexNode.Index, context))
 	if res.ShouldReturn() {
 		return res
 	}
 
-	if _, ok := startVal.(common.Number); !ok {
-		return res.Failure(common.RTError(*arrayNode.StartNode.GetPosStart(), *arrayNode.StartNode.GetPosEnd(), "Start index must be a number", context))
+	if text, ok := left.(common.String); ok {
+		karakter := []rune(text.Value)
+		index, errorNya := indexDariSatu(indexVal, len(karakter), "String", indexNode.Index, context)
+		if errorNya != nil {
+			return res.Failure(*errorNya)
+		}
+		return res.Success(common.String{Value: string(karakter[index-1]), Context: context})
 	}
-	if _, ok := endVal.(common.Number); !ok {
-		return res.Failure(common.RTError(*arrayNode.EndNode.GetPosStart(), *arrayNode.EndNode.GetPosEnd(), "End index must be a number", context))
+
+	if list, ok := left.(common.List); ok {
+		index, errorNya := indexDariSatu(indexVal, len(list.Elements), "List", indexNode.Index, context)
+		if errorNya != nil {
+			return res.Failure(*errorNya)
+		}
+		return res.Success(list.Elements[index-1])
 	}
 
-	start := int(startVal.(common.Number).Value)
-	end := int(endVal.(common.Number).Value)
-	size := end - start + 1
+	array, ok := left.(common.Array)
+	if !ok {
+		return res.Failure(common.RTError(*indexNode.Left.GetPosStart(), *indexNode.Left.GetPosEnd(), bukanArray(indexNode), context))
+	}
 
-	if size < 0 {
-		return res.Failure(common.RTError(*node.GetPosStart(), *node.GetPosEnd(), "Array start index must be less than or equal to end index", context))
+	if _, ok := indexVal.(common.Number); !ok {
+		return res.Failure(common.RTError(*indexNode.Index.GetPosStart(), *indexNode.Index.GetPosEnd(), "Array index must be a number", context))
 	}
 
-	elements := make([]common.Value, size)
-	for j := 0; j < size; j++ {
-		elemVal := res.Register(i.InitializeType(arrayNode.OfType, context))
+	index := int(indexVal.(common.Number).Value)
+	if index < array.Start || index > array.End {
+		return res.Failure(common.RTError(*indexNode.Index.GetPosStart(), *indexNode.Index.GetPosEnd(), fmt.Sprintf("Index %d out of bounds [%d..%d]%s", index, array.Start, array.End, diDimensi(indexNode)), context))
+	}
+
+	return res.Success(array.Elements[index-array.Start])
+}
+
+// VisitSliceNode gives the elements a to b of a list or an array as a new
+// list, or the characters a to b of a string. b may be a - 1 for an empty
+// slice.
+func (i *Interpreter) VisitSliceNode(node common.Expr, context *common.Context) common.Value {
+	res := &common.RTResult{}
+	sliceNode := node.(common.SliceNode)
+
+	left := res.Register(i.Visit(sliceNode.Left, context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	var elements []common.Value
+	awal := 1
+	switch left := left.(type) {
+	case common.String:
+		for _, karakter := range left.Value {
+			elements = append(elements, common.String{Value: string(karakter)})
+		}
+	case common.List:
+		elements = left.Elements
+	case common.Array:
+		elements = left.Elements
+		awal = left.Start
+	default:
+		return res.Failure(common.RTError(*sliceNode.Left.GetPosStart(), *sliceNode.Left.GetPosEnd(), fmt.Sprintf("Only a list, an array or a string can be sliced, not a %s", common.NamaTipe(left)), context))
+	}
+	akhir := awal + len(elements) - 1
+
+	batas := [2]int{}
+	for idx, batasNode := range []common.Expr{sliceNode.Low, sliceNode.High} {
+		value := res.Register(i.Visit(batasNode, context))
 		if res.ShouldReturn() {
 			return res
 		}
-		elements[j] = elemVal
+		number, ok := value.(common.Number)
+		if !ok || number.Value != math.Trunc(number.Value) {
+			return res.Failure(common.RTError(*batasNode.GetPosStart(), *batasNode.GetPosEnd(), "The bounds of a slice must be integers", context))
+		}
+		batas[idx] = int(number.Value)
+	}
+
+	low, high := batas[0], batas[1]
+	if low < awal || high > akhir || high < low-1 {
+		return res.Failure(common.RTError(*node.GetPosStart(), *node.GetPosEnd(), fmt.Sprintf("Slice [%d..%d] out of bounds [%d..%d]", low, high, awal, akhir), context))
 	}
 
-	return res.Success(common.Array{
-		Elements:  elements,
-		Start:     start,
-		End:       end,
-		Context:   context,
-		Pos_Start: node.GetPosStart(),
-		Pos_End:   node.GetPosEnd(),
-	})
+	if text, ok := left.(common.String); ok {
+		return res.Success(common.String{Value: string([]rune(text.Value)[low-1 : high]), Context: context})
+	}
+	potongan := make([]common.Value, 0, high-low+1)
+	for _, element := range elements[low-awal : high-awal+1] {
+		potongan = append(potongan, element.Copy())
+	}
+	return res.Success(common.MilikSendiri(common.List{Elements: potongan, Context: context}))
 }
 
-func (i *Interpreter) VisitArrayIndexNode(node common.Expr, context *common.Context) common.Value {
+func (i *Interpreter) VisitArrayAssignNode(node common.Expr, context *common.Context) common.Value {
 	res := &common.RTResult{}
-	indexNode := node.(common.ArrayIndexNode)
+	assignNode := node.(common.ArrayAssignNode)
 
-	left := res.Register(i.Visit(indexNode.Left, context))
+	value := res.Register(i.Visit(assignNode.ValueNode, context))
 	if res.ShouldReturn() {
 		return res
 	}
 
-	indexVal := res.Register(i.Visit(indexNode.Index, context))
+	return i.simpanDi(assignNode.ArrayAccess, value, assignNode.ValueNode, context)
+}
+
+// simpanDi stores value at an element or a field, for assignments and for
+// read. valueNode locates errors about the value.
+func (i *Interpreter) simpanDi(target common.Expr, value common.Value, valueNode common.Expr, context *common.Context) common.Value {
+	res := &common.RTResult{}
+
+	res.Register(i.cekNilai(i.tipeDeklarasi(target, context), value, target.Print(), valueNode.GetPosStart(), valueNode.GetPosEnd(), context))
 	if res.ShouldReturn() {
 		return res
 	}
 
-	array, ok := left.(common.Array)
+	res.Register(i.ubahTempat(target, func(common.Value) common.Value {
+		return value.Copy()
+	}, valueNode, context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	return res.Success(value)
+}
+
+// cekTambahan checks the values append and insert add to a list of T
+// against T before they go in: what an assignment to the element checks, and
+// that an integer, real or string element gets a whole number, a number or a
+// string.
+func (i *Interpreter) cekTambahan(builtin common.BuiltInFunction, rawArgs []common.Expr, args []common.Value, context *common.Context) common.Value {
+	res := &common.RTResult{}
+
+	fungsi := builtin.Name
+	if builtin.Terdaftar || (fungsi != "Append" && fungsi != "Insert") || len(args) == 0 {
+		return res.Success(common.Null{})
+	}
+	tipe, ok := i.tipeDeklarasi(rawArgs[0], context).(common.ListTypeNode)
+	if !ok {
+		return res.Success(common.Null{})
+	}
+	list, ok := args[0].(common.List)
 	if !ok {
-		return res.Failure(common.RTError(*indexNode.Left.GetPosStart(), *indexNode.Left.GetPosEnd(), "Left hand side is not an array", context))
+		return res.Success(common.Null{})
 	}
 
-	if _, ok := indexVal.(common.Number); !ok {
-		return res.Failure(common.RTError(*indexNode.Index.GetPosStart(), *indexNode.Index.GetPosEnd(), "Array index must be a number", context))
+	for k := 1; k < len(args); k++ {
+		posisi := fmt.Sprint(len(list.Elements) + k)
+		if fungsi == "Insert" {
+			if k != 2 {
+				continue
+			}
+			posisi = common.PrintValueInterpreter(args[1])
+		}
+		nama := fmt.Sprintf("%s[%s]", rawArgs[0].Print(), posisi)
+		if dasar, ok := i.resolveTipe(tipe.OfType, context).(common.VarAccessNode); ok {
+			if jenis, cocok := cocokDasar(dasar.VarNameTok.Value, args[k]); !cocok {
+				lain := "a " + common.NamaTipe(args[k])
+				if _, ok := args[k].(common.Number); ok {
+					lain = common.PrintValueInterpreter(args[k])
+				}
+				return res.Failure(common.RTError(*rawArgs[k].GetPosStart(), *rawArgs[k].GetPosEnd(), fmt.Sprintf("'%s' holds %s, not %s", nama, jenis, lain), context))
+			}
+		}
+		res.Register(i.cekNilai(tipe.OfType, args[k], nama, rawArgs[k].GetPosStart(), rawArgs[k].GetPosEnd(), context))
+		if res.ShouldReturn() {
+			return res
+		}
 	}
+	return res.Success(common.Null{})
+}
 
-	index := int(indexVal.(common.Number).Value)
-	if index < array.Start || index > array.End {
-		return res.Failure(common.RTError(*indexNode.Index.GetPosStart(), *indexNode.Index.GetPosEnd(), fmt.Sprintf("Index %d out of bounds [%d..%d]", index, array.Start, array.End), context))
+// cocokDasar tells whether value fits the basic type nama, and names that type
+// for the error when it doesn't. Other types always fit here.
+func cocokDasar(nama string, value common.Value) (string, bool) {
+	switch nama {
+	case "integer":
+		angka, ok := value.(common.Number)
+		return "an integer", ok && angka.Value == math.Trunc(angka.Value)
+	case "real":
+		_, ok := value.(common.Number)
+		return "a real", ok
+	case "string":
+		_, ok := value.(common.String)
+		return "a string", ok
+	}
+	return "", true
+}
+
+// akarTempat finds the variable an element or a field belongs to. A place
+// reached through a pointer belongs to no variable.
+func akarTempat(target common.Expr) (common.VarAccessNode, bool) {
+	for {
+		switch t := target.(type) {
+		case common.VarAccessNode:
+			return t, true
+		case common.ArrayIndexNode:
+			target = t.Left
+		case common.MemberAccessNode:
+			target = t.Object
+		default:
+			return common.VarAccessNode{}, false
+		}
 	}
+}
 
-	return res.Success(array.Elements[index-array.Start])
+// ubahTempat replaces what is stored at target, an element or a field at any
+// depth below a variable, with ganti applied to it. Arrays, lists and structs
+// on the way are made owned first, so a copy of them elsewhere doesn't see the
+// change. It returns the new value of target.
+func (i *Interpreter) ubahTempat(target common.Expr, ganti func(common.Value) common.Value, valueNode common.Expr, context *common.Context) common.Value {
+	res := &common.RTResult{}
+
+	switch t := target.(type) {
+	case common.VarAccessNode:
+		nama := t.VarNameTok.Value
+		tabel := context.Symbol_Table
+		for tabel != nil {
+			if _, ok := tabel.Symbols[nama]; ok {
+				break
+			}
+			tabel = tabel.Parent
+		}
+		if tabel == nil {
+			return res.Failure(common.RTError(*t.Pos_Start, *t.Pos_end, fmt.Sprintf("'%s' is not defined", nama), context))
+		}
+
+		lama := tabel.Symbols[nama]
+		baru := res.Register(ganti(lama))
+		if res.ShouldReturn() {
+			return res
+		}
+
+		// A string is a plain value, so changing one of its characters is an
+		// assignment to the variable.
+		if _, ok := lama.(common.String); ok {
+			res.Register(i.GantiVariable(nama, baru, context, false, t.Pos_Start, t.Pos_end))
+			if res.Error != nil {
+				return res
+			}
+			return res.Success(baru)
+		}
+
+		tabel.Set(nama, baru)
+		return res.Success(baru)
+
+	case common.ArrayIndexNode:
+		indexVal := res.Register(i.Visit(t.Index, context))
+		if res.ShouldReturn() {
+			return res
+		}
+
+		return i.ubahTempat(t.Left, func(wadah common.Value) common.Value {
+			res := &common.RTResult{}
+
+			switch w := wadah.(type) {
+			case common.String:
+				karakter := []rune(w.Value)
+				index, errorNya := indexDariSatu(indexVal, len(karakter), "String", t.Index, context)
+				if errorNya != nil {
+					return res.Failure(*errorNya)
+				}
+
+				baru := res.Register(ganti(common.String{Value: string(karakter[index-1])}))
+				if res.ShouldReturn() {
+					return res
+				}
+				pengganti, apakahString := baru.(common.String)
+				if !apakahString || len([]rune(pengganti.Value)) != 1 {
+					return res.Failure(common.RTError(*valueNode.GetPosStart(), *valueNode.GetPosEnd(), "Only a single character can be assigned to a string index", context))
+				}
+
+				karakter[index-1] = []rune(pengganti.Value)[0]
+				return res.Success(common.String{Value: string(karakter), Context: context})
+
+			case common.List:
+				index, errorNya := indexDariSatu(indexVal, len(w.Elements), "List", t.Index, context)
+				if errorNya != nil {
+					return res.Failure(*errorNya)
+				}
+
+				list := common.SiapDiubah(w).(common.List)
+				list.Elements[index-1] = res.Register(ganti(list.Elements[index-1]))
+				if res.ShouldReturn() {
+					return res
+				}
+				return res.Success(list)
+
+			case common.Array:
+				if _, ok := indexVal.(common.Number); !ok {
+					return res.Failure(common.RTError(*t.Index.GetPosStart(), *t.Index.GetPosEnd(), "Array index must be a number", context))
+				}
+
+				index := int(indexVal.(common.Number).Value)
+				if index < w.Start || index > w.End {
+					return res.Failure(common.RTError(*t.Index.GetPosStart(), *t.Index.GetPosEnd(), fmt.Sprintf("Index %d out of bounds [%d..%d]%s", index, w.Start, w.End, diDimensi(t)), context))
+				}
+
+				array := common.SiapDiubah(w).(common.Array)
+				array.Elements[index-array.Start] = res.Register(ganti(array.Elements[index-array.Start]))
+				if res.ShouldReturn() {
+					return res
+				}
+				return res.Success(array)
+			}
+
+			return res.Failure(common.RTError(*t.Left.GetPosStart(), *t.Left.GetPosEnd(), bukanArray(t), context))
+		}, valueNode, context)
+
+	case common.DerefNode:
+		// What p points to is changed in place, for every copy of p; p itself
+		// stays as it is.
+		pointer, err := i.ambilPointer(t, context)
+		if err != nil {
+			return res.Failure(*err)
+		}
+
+		lama, _ := pointer.Isi()
+		baru := res.Register(ganti(lama))
+		if res.ShouldReturn() {
+			return res
+		}
+		pointer.Ganti(baru)
+		return res.Success(baru)
+
+	case common.MemberAccessNode:
+		return i.ubahTempat(t.Object, func(object common.Value) common.Value {
+			res := &common.RTResult{}
+
+			structVal, ok := object.(common.Struct)
+			if !ok {
+				return res.Failure(common.RTError(*t.Object.GetPosStart(), *t.Object.GetPosEnd(), bukanStruct(object, t), context))
+			}
+
+			nama := t.MemberTok.Value
+			if _, ok := structVal.Fields[nama]; !ok {
+				return res.Failure(common.RTError(*t.MemberTok.Pos_Start, *t.MemberTok.Pos_End, fmt.Sprintf("Field '%s' not found in struct", nama), context))
+			}
+
+			structVal = common.SiapDiubah(structVal).(common.Struct)
+			structVal.Fields[nama] = res.Register(ganti(structVal.Fields[nama]))
+			if res.ShouldReturn() {
+				return res
+			}
+			return res.Success(structVal)
+		}, valueNode, context)
+	}
+
+	return res.Failure(common.RTError(*target.GetPosStart(), *target.GetPosEnd(), "Only a variable, an element of one or a field of one can be changed", context))
 }
 
-func (i *Interpreter) VisitArrayAssignNode(node common.Expr, context *common.Context) common.Value {
+func (i *Interpreter) VisitFormatNode(node common.Expr, context *common.Context) common.Value {
 	res := &common.RTResult{}
-	assignNode := node.(common.ArrayAssignNode)
+	formatNode := node.(common.FormatNode)
 
-	left := res.Register(i.Visit(assignNode.ArrayAccess.Left, context))
+	value := res.Register(i.Visit(formatNode.Value, context))
 	if res.ShouldReturn() {
 		return res
 	}
 
-	indexVal := res.Register(i.Visit(assignNode.ArrayAccess.Index, context))
-	if res.ShouldReturn() {
-		return res
+	width, errorNya := i.ukuranKolom(formatNode.Width, "Field width", context)
+	if errorNya != nil {
+		return res.Failure(*errorNya)
 	}
 
-	value := res.Register(i.Visit(assignNode.ValueNode, context))
+	precision := -1
+	if formatNode.Precision != nil {
+		precision, errorNya = i.ukuranKolom(formatNode.Precision, "Number of decimals", context)
+		if errorNya != nil {
+			return res.Failure(*errorNya)
+		}
+	}
+
+	hasil, err := common.FormatKolom(value, width, precision)
+	if err != nil {
+		return res.Failure(common.RTError(*node.GetPosStart(), *node.GetPosEnd(), err.Error(), context))
+	}
+
+	return res.Success(common.String{Value: hasil, Context: context})
+}
+
+// ukuranKolom evaluates the width or precision of a write argument, which
+// must be a non-negative integer.
+func (i *Interpreter) ukuranKolom(node common.Expr, nama string, context *common.Context) (int, *common.Error) {
+	res := &common.RTResult{}
+	value := res.Register(i.Visit(node, context))
 	if res.ShouldReturn() {
-		return res
+		return 0, res.Error
 	}
 
-	array, ok := left.(common.Array)
-	if !ok {
-		return res.Failure(common.RTError(*assignNode.ArrayAccess.Left.GetPosStart(), *assignNode.ArrayAccess.Left.GetPosEnd(), "Left hand side is not an array", context))
+	number, ok := value.(common.Number)
+	if !ok || number.Value < 0 || number.Value != math.Trunc(number.Value) {
+		errorNya := common.RTError(*node.GetPosStart(), *node.GetPosEnd(), fmt.Sprintf("%s must be a non-negative integer, got %s", nama, common.PrintValueInterpreter(value)), context)
+		return 0, &errorNya
 	}
 
-	if _, ok := indexVal.(common.Number); !ok {
-		return res.Failure(common.RTError(*assignNode.ArrayAccess.Index.GetPosStart(), *assignNode.ArrayAccess.Index.GetPosEnd(), "Array index must be a number", context))
+	return int(number.Value), nil
+}
+
+// bukanArray explains an index on something that isn't an array; for m[i, j]
+// that means m has fewer dimensions than indices.
+func bukanArray(node common.ArrayIndexNode) string {
+	if node.Dimension <= 1 {
+		return "Left hand side is not an array"
 	}
 
-	index := int(indexVal.(common.Number).Value)
-	if index < array.Start || index > array.End {
-		return res.Failure(common.RTError(*assignNode.ArrayAccess.Index.GetPosStart(), *assignNode.ArrayAccess.Index.GetPosEnd(), fmt.Sprintf("Index %d out of bounds [%d..%d]", index, array.Start, array.End), context))
+	akar := node.Left
+	for {
+		index, ok := akar.(common.ArrayIndexNode)
+		if !ok || index.Dimension == 0 {
+			break
+		}
+		akar = index.Left
+	}
+	return fmt.Sprintf("'%s' has no dimension %d", akar.Print(), node.Dimension)
+}
+
+// diDimensi names the dimension of an index of m[i, j] in bounds errors.
+func diDimensi(node common.ArrayIndexNode) string {
+	if node.Dimension == 0 {
+		return ""
 	}
+	return fmt.Sprintf(" in dimension %d", node.Dimension)
+}
 
-	array.Elements[index-array.Start] = value
-	return res.Success(value)
+// indexDariSatu checks a 1-based string or list index against its length.
+func indexDariSatu(indexVal common.Value, panjang int, jenis string, indexNode common.Expr, context *common.Context) (int, *common.Error) {
+	number, ok := indexVal.(common.Number)
+	if !ok {
+		errorNya := common.RTError(*indexNode.GetPosStart(), *indexNode.GetPosEnd(), jenis+" index must be a number", context)
+		return 0, &errorNya
+	}
+
+	index := int(number.Value)
+	if index < 1 || index > panjang {
+		errorNya := common.RTError(*indexNode.GetPosStart(), *indexNode.GetPosEnd(), fmt.Sprintf("Index %d out of bounds [1..%d]", index, panjang), context)
+		return 0, &errorNya
+	}
+
+	return index, nil
+}
+
+// bukanStruct is the error for a field of something that isn't a struct,
+// pointing a pointer to p^.field.
+func bukanStruct(object common.Value, node common.MemberAccessNode) string {
+	if _, ok := object.(common.Pointer); ok {
+		return fmt.Sprintf("'%s' is a pointer, write %s^.%s for the field of what it points to", node.Object.Print(), node.Object.Print(), node.MemberTok.Value)
+	}
+	return "Object is not a struct"
 }
 
 func (i *Interpreter) VisitMemberAccessNode(node common.Expr, context *common.Context) common.Value {
@@ -739,7 +1463,7 @@ func (i *Interpreter) VisitMemberAccessNode(node common.Expr, context *common.Co
 
 	structVal, ok := object.(common.Struct)
 	if !ok {
-		return res.Failure(common.RTError(*accessNode.Object.GetPosStart(), *accessNode.Object.GetPosEnd(), "Object is not a struct", context))
+		return res.Failure(common.RTError(*accessNode.Object.GetPosStart(), *accessNode.Object.GetPosEnd(), bukanStruct(object, accessNode), context))
 	}
 
 	val, ok := structVal.Fields[accessNode.MemberTok.Value]
@@ -754,27 +1478,12 @@ func (i *Interpreter) VisitMemberAssignNode(node common.Expr, context *common.Co
 	res := &common.RTResult{}
 	assignNode := node.(common.MemberAssignNode)
 
-	object := res.Register(i.Visit(assignNode.MemberAccess.Object, context))
-	if res.ShouldReturn() {
-		return res
-	}
-
 	value := res.Register(i.Visit(assignNode.ValueNode, context))
 	if res.ShouldReturn() {
 		return res
 	}
 
-	structVal, ok := object.(common.Struct)
-	if !ok {
-		return res.Failure(common.RTError(*assignNode.MemberAccess.Object.GetPosStart(), *assignNode.MemberAccess.Object.GetPosEnd(), "Object is not a struct", context))
-	}
-
-	if _, ok := structVal.Fields[assignNode.MemberAccess.MemberTok.Value]; !ok {
-		return res.Failure(common.RTError(*assignNode.MemberAccess.MemberTok.Pos_Start, *assignNode.MemberAccess.MemberTok.Pos_End, fmt.Sprintf("Field '%s' not found in struct", assignNode.MemberAccess.MemberTok.Value), context))
-	}
-
-	structVal.Fields[assignNode.MemberAccess.MemberTok.Value] = value
-	return res.Success(value)
+	return i.simpanDi(assignNode.MemberAccess, value, assignNode.ValueNode, context)
 }
 
 func (i *Interpreter) VisitTypeAliasNode(node common.Expr, context *common.Context) common.Value {
@@ -787,6 +1496,18 @@ func (i *Interpreter) VisitTypeAliasNode(node common.Expr, context *common.Conte
 		Pos_End:    aliasNode.Pos_End,
 	}
 	context.Symbol_Table.Set(aliasNode.AliasName.Value, typeValue)
+
+	// The values of an enumeration are constants named after them.
+	if enum, ok := aliasNode.TargetType.(common.EnumTypeNode); ok {
+		nama := enum.NamaNilai()
+		for ordinal, tok := range enum.Values {
+			if _, ada := context.Symbol_Table.Symbols[tok.Value]; ada {
+				return res.Failure(common.RTError(*tok.Pos_Start, *tok.Pos_End, fmt.Sprintf("'%s' is already defined", tok.Value), context))
+			}
+			context.Symbol_Table.Set(tok.Value, common.Enum{Tipe: enum.TypeTok.Value, Nama: nama, Ordinal: ordinal, Context: context})
+			context.Symbol_Table.Set("ApakahKonstant "+tok.Value, common.Number{Value: 1})
+		}
+	}
 	return res.Success(common.Null{})
 }
 
@@ -803,6 +1524,74 @@ func (i *Interpreter) VisitStructTypeNode(node common.Expr, context *common.Cont
 	return res.Success(common.Null{})
 }
 
+// VisitStructLiteralNode builds a struct from its default value, then sets the
+// fields the literal gives.
+func (i *Interpreter) VisitStructLiteralNode(node common.Expr, context *common.Context) common.Value {
+	res := &common.RTResult{}
+	literal := node.(common.StructLiteralNode)
+
+	value := res.Register(i.InitializeType(common.VarAccessNode{VarNameTok: literal.TypeTok, Pos_Start: literal.TypeTok.Pos_Start, Pos_end: literal.TypeTok.Pos_End}, context))
+	if res.ShouldReturn() {
+		return res
+	}
+	structVal, ok := value.(common.Struct)
+	if !ok {
+		return res.Failure(common.RTError(*literal.TypeTok.Pos_Start, *literal.TypeTok.Pos_End, fmt.Sprintf("'%s' is not a struct type", literal.TypeTok.Value), context))
+	}
+
+	// Fields of a subrange or an enumeration type are checked like variables.
+	tipeField := map[string]common.Expr{}
+	if definisi, ok := i.resolveTipe(common.VarAccessNode{VarNameTok: literal.TypeTok}, context).(common.StructTypeNode); ok {
+		for _, field := range definisi.Fields {
+			tipeField[field.VarName.Value] = i.resolveTipe(field.ValueNode, context)
+		}
+	}
+
+	if literal.Positional {
+		if selisih := len(literal.Values) - len(structVal.Urutan); selisih > 0 {
+			return res.Failure(common.RTError(*literal.Pos_Start, *literal.Pos_End, fmt.Sprintf("%d too many values passed into '%s'", selisih, literal.TypeTok.Value), context))
+		} else if selisih < 0 {
+			return res.Failure(common.RTError(*literal.Pos_Start, *literal.Pos_End, fmt.Sprintf("%d too few values passed into '%s'", -selisih, literal.TypeTok.Value), context))
+		}
+		for idx, valueNode := range literal.Values {
+			fieldVal := res.Register(i.Visit(valueNode, context))
+			if res.ShouldReturn() {
+				return res
+			}
+			nama := structVal.Urutan[idx]
+			res.Register(i.cekNilai(tipeField[nama], fieldVal, literal.TypeTok.Value+"."+nama, valueNode.GetPosStart(), valueNode.GetPosEnd(), context))
+			if res.ShouldReturn() {
+				return res
+			}
+			structVal.Fields[nama] = fieldVal.Copy()
+		}
+	} else {
+		diberi := map[string]bool{}
+		for _, field := range literal.Fields {
+			nama := field.VarName.Value
+			if _, ok := structVal.Fields[nama]; !ok {
+				return res.Failure(common.RTError(*field.VarName.Pos_Start, *field.VarName.Pos_End, fmt.Sprintf("Field '%s' not found in struct", nama), context))
+			}
+			if diberi[nama] {
+				return res.Failure(common.RTError(*field.VarName.Pos_Start, *field.VarName.Pos_End, fmt.Sprintf("Field '%s' is given twice", nama), context))
+			}
+			diberi[nama] = true
+
+			fieldVal := res.Register(i.Visit(field.ValueNode, context))
+			if res.ShouldReturn() {
+				return res
+			}
+			res.Register(i.cekNilai(tipeField[nama], fieldVal, literal.TypeTok.Value+"."+nama, field.ValueNode.GetPosStart(), field.ValueNode.GetPosEnd(), context))
+			if res.ShouldReturn() {
+				return res
+			}
+			structVal.Fields[nama] = fieldVal.Copy()
+		}
+	}
+
+	return res.Success(structVal.Set_pos(literal.Pos_Start, literal.Pos_End))
+}
+
 func (i *Interpreter) InitializeType(typeNode common.Expr, context *common.Context) common.Value {
 	res := &common.RTResult{}
 
@@ -812,7 +1601,7 @@ func (i *Interpreter) InitializeType(typeNode common.Expr, context *common.Conte
 		switch typeName {
 		case "integer", "real":
 			return res.Success(common.Number{Value: 0, Context: context})
-		case "string":
+		case "string", "character":
 			return res.Success(common.String{Value: "", Context: context})
 		default:
 			val := context.Symbol_Table.Get(typeName)
@@ -822,17 +1611,7 @@ func (i *Interpreter) InitializeType(typeNode common.Expr, context *common.Conte
 			return res.Failure(common.RTError(*t.GetPosStart(), *t.GetPosEnd(), fmt.Sprintf("Unknown type '%s'", typeName), context))
 		}
 	case common.ArrayTypeNode:
-		startVal := res.Register(i.Visit(t.StartNode, context))
-		if res.ShouldReturn() {
-			return res
-		}
-		endVal := res.Register(i.Visit(t.EndNode, context))
-		if res.ShouldReturn() {
-			return res
-		}
-
-		start := int(startVal.(common.Number).Value)
-		end := int(endVal.(common.Number).Value)
+		start, end := t.Start, t.End
 		size := end - start + 1
 		elements := make([]common.Value, size)
 
@@ -844,14 +1623,40 @@ func (i *Interpreter) InitializeType(typeNode common.Expr, context *common.Conte
 			elements[idx] = elemVal
 		}
 
-		return res.Success(common.Array{
+		return res.Success(common.MilikSendiri(common.Array{
 			Elements:  elements,
 			Start:     start,
 			End:       end,
 			Context:   context,
 			Pos_Start: t.Pos_Start,
 			Pos_End:   t.Pos_End,
-		})
+		}))
+	case common.EnumTypeNode:
+		return res.Success(common.Enum{Tipe: t.TypeTok.Value, Nama: t.NamaNilai(), Context: context})
+	case common.PointerTypeNode:
+		return res.Success(common.Pointer{Tipe: t.OfType, Context: context})
+	case common.SubrangeTypeNode:
+		low, _, err := i.batasSubrange(t, context)
+		if err != nil {
+			return res.Failure(*err)
+		}
+		return res.Success(low)
+	case common.ListTypeNode:
+		return res.Success(common.MilikSendiri(common.List{
+			Elements:  []common.Value{},
+			Context:   context,
+			Pos_Start: t.Pos_Start,
+			Pos_End:   t.Pos_End,
+		}))
+	case common.FileTypeNode:
+		if t.OfType == nil {
+			return res.Success(common.NewFile(nil).Set_context(context))
+		}
+		elemen := res.Register(i.tipeElemen(t.OfType, context))
+		if res.ShouldReturn() {
+			return res
+		}
+		return res.Success(common.NewFile(elemen.(common.Type).Definition).Set_context(context))
 	case common.StructTypeNode:
 		fields := make(map[string]common.Value)
 		for _, field := range t.Fields {
@@ -861,12 +1666,14 @@ func (i *Interpreter) InitializeType(typeNode common.Expr, context *common.Conte
 			}
 			fields[field.VarName.Value] = fieldVal
 		}
-		return res.Success(common.Struct{
+		return res.Success(common.MilikSendiri(common.Struct{
+			Nama:      t.StructName.Value,
 			Fields:    fields,
+			Urutan:    t.NamaField(),
 			Context:   context,
 			Pos_Start: t.Pos_Start,
 			Pos_End:   t.Pos_End,
-		})
+		}))
 	}
 
 	return res.Success(common.Null{})
@@ -0,0 +1,159 @@
+package interpreter_test
+
+import (
+	"dap/internal/common"
+	"dap/internal/testutil"
+	"testing"
+)
+
+// Each variable holds its own value: changing one never shows in another it
+// was copied from or to.
+func TestNilaiTidakBerbagi(t *testing.T) {
+	tests := []struct {
+		nama   string
+		source string
+		ingin  map[string]string
+	}{
+		{
+			nama: "assignment",
+			source: `program P
+dictionary
+  a, b : array[1..3] of integer
+algorithm
+  a[1] <- 1
+  a[2] <- 2
+  a[3] <- 3
+  b <- a
+  b[1] <- 9
+endprogram`,
+			ingin: map[string]string{"a": "[1, 2, 3]", "b": "[9, 2, 3]"},
+		},
+		{
+			nama: "parameter",
+			source: `program P
+dictionary
+  a, hasil : list of integer
+algorithm
+  function gandakan(arr)
+    arr[1] <- arr[1] * 2
+    append(arr, 4)
+    return arr
+  end
+
+  a <- [1, 2, 3]
+  hasil <- gandakan(a)
+endprogram`,
+			ingin: map[string]string{"a": "[1, 2, 3]", "hasil": "[2, 2, 3, 4]"},
+		},
+		{
+			nama: "nested list",
+			source: `program P
+dictionary
+  xs, ys : list of list of integer
+algorithm
+  xs <- [[1, 2], [3]]
+  ys <- xs
+  ys[1][2] <- 20
+  append(ys[2], 5)
+endprogram`,
+			ingin: map[string]string{"xs": "[[1, 2], [3]]", "ys": "[[1, 20], [3, 5]]"},
+		},
+		{
+			nama: "struct in array",
+			source: `program P
+dictionary
+  type Point <
+    x : integer
+    y : integer
+  >
+  p : Point
+  titik, salinan : array[1..2] of Point
+algorithm
+  p.x <- 1
+  p.y <- 2
+  titik[1] <- p
+  titik[1].y <- 7
+  salinan <- titik
+  salinan[1].x <- 5
+endprogram`,
+			ingin: map[string]string{
+				"p":          "<x: 1, y: 2>",
+				"titik[1]":   "<x: 1, y: 7>",
+				"salinan[1]": "<x: 5, y: 7>",
+			},
+		},
+		{
+			nama: "append after copy",
+			source: `program P
+dictionary
+  type Bag <
+    items : list of integer
+  >
+  b, c : Bag
+  xs, ys : list of integer
+algorithm
+  xs <- [1]
+  append(xs, 2)
+  ys <- xs
+  append(xs, 3)
+  append(ys, 4)
+  b.items <- [1]
+  append(b.items, 2)
+  c <- b
+  append(b.items, 3)
+  b.items[1] <- 9
+  append(c.items, 4)
+endprogram`,
+			ingin: map[string]string{
+				"xs": "[1, 2, 3]",
+				"ys": "[1, 2, 4]",
+				"b":  "<items: [9, 2, 3]>",
+				"c":  "<items: [1, 2, 4]>",
+			},
+		},
+		{
+			nama: "foreach",
+			source: `program P
+dictionary
+  xs : list of list of integer
+  x : list of integer
+algorithm
+  xs <- [[1], [2]]
+  foreach x in xs do
+    x[1] <- 0
+    append(x, 9)
+  endforeach
+endprogram`,
+			ingin: map[string]string{"xs": "[[1], [2]]", "x": "[0, 9]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nama, func(t *testing.T) {
+			tabel, errorNya := testutil.Jalankan(t, tt.source)
+			if errorNya != nil {
+				t.Fatal(errorNya.As_string())
+			}
+			for nama, ingin := range tt.ingin {
+				if got := common.PrintValueInterpreter(ambilNilai(t, tabel, nama)); got != ingin {
+					t.Errorf("%s = %s, want %s", nama, got, ingin)
+				}
+			}
+		})
+	}
+}
+
+// ambilNilai reads a variable, or the first element of one as in titik[1].
+func ambilNilai(t *testing.T, tabel *common.SymbolTable, nama string) common.Value {
+	t.Helper()
+
+	if len(nama) > 3 && nama[len(nama)-3:] == "[1]" {
+		switch v := tabel.Get(nama[:len(nama)-3]).(type) {
+		case common.Array:
+			return v.Elements[1-v.Start]
+		case common.List:
+			return v.Elements[0]
+		}
+	}
+	return tabel.Get(nama)
+}
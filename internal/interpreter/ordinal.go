@@ -0,0 +1,85 @@
+package interpreter
+
+import (
+	"dap/internal/common"
+	"dap/tools"
+	"fmt"
+)
+
+// batasSubrange evaluates the bounds of a subrange type. Both must be values
+// of one ordinal kind, whole numbers, characters or values of one
+// enumeration, and the low one can't be above the high one.
+func (i *Interpreter) batasSubrange(t common.SubrangeTypeNode, context *common.Context) (common.Value, common.Value, *common.Error) {
+	res := &common.RTResult{}
+
+	low := res.Register(i.Visit(t.Low, context))
+	if res.ShouldReturn() {
+		return nil, nil, res.Error
+	}
+	high := res.Register(i.Visit(t.High, context))
+	if res.ShouldReturn() {
+		return nil, nil, res.Error
+	}
+
+	lo, jenisLow, okLow := common.NilaiOrdinal(low)
+	hi, jenisHigh, okHigh := common.NilaiOrdinal(high)
+	if !okLow || !okHigh || jenisLow != jenisHigh {
+		err := common.RTError(*t.Pos_Start, *t.Pos_End, fmt.Sprintf("The bounds of the subrange %s must be two whole numbers, two characters or two values of one enumeration", t.Print()), context)
+		return nil, nil, &err
+	}
+	if lo > hi {
+		err := common.RTError(*t.Pos_Start, *t.Pos_End, fmt.Sprintf("The subrange %s is empty, its low bound is above its high bound", t.Print()), context)
+		return nil, nil, &err
+	}
+
+	return low, high, nil
+}
+
+// cekNilai checks a value about to be stored in nama, whose declared type is
+// tipe: a subrange only takes values between its bounds, an enumeration only
+// takes its own values, a pointer only pointers and a character only strings
+// of one character. Other types aren't checked.
+func (i *Interpreter) cekNilai(tipe common.Expr, value common.Value, nama string, posStart *tools.Position, posEnd *tools.Position, context *common.Context) common.Value {
+	res := &common.RTResult{}
+
+	switch t := i.resolveTipe(tipe, context).(type) {
+	case common.VarAccessNode:
+		if t.VarNameTok.Value != "character" {
+			break
+		}
+		if teks, ok := value.(common.String); !ok {
+			return res.Failure(common.RTError(*posStart, *posEnd, fmt.Sprintf("'%s' holds a character, not a %s", nama, common.NamaTipe(value)), context))
+		} else if len([]rune(teks.Value)) != 1 {
+			return res.Failure(common.RTError(*posStart, *posEnd, fmt.Sprintf("'%s' holds a single character, not %q", nama, teks.Value), context))
+		}
+	case common.EnumTypeNode:
+		if v, ok := value.(common.Enum); !ok || v.Tipe != t.TypeTok.Value {
+			return res.Failure(common.RTError(*posStart, *posEnd, fmt.Sprintf("'%s' holds a %s, not a %s", nama, t.TypeTok.Value, common.NamaTipe(value)), context))
+		}
+	case common.PointerTypeNode:
+		if _, ok := value.(common.Pointer); !ok {
+			return res.Failure(common.RTError(*posStart, *posEnd, fmt.Sprintf("'%s' holds a pointer, not a %s", nama, common.NamaTipe(value)), context))
+		}
+	case common.SubrangeTypeNode:
+		low, high, err := i.batasSubrange(t, context)
+		if err != nil {
+			return res.Failure(*err)
+		}
+
+		lo, jenis, _ := common.NilaiOrdinal(low)
+		hi, _, _ := common.NilaiOrdinal(high)
+		ordinal, jenisNilai, ok := common.NilaiOrdinal(value)
+		if !ok || jenisNilai != jenis {
+			bukan := "a " + common.NamaTipe(value)
+			if _, ok := value.(common.Number); ok {
+				bukan = common.PrintValueInterpreter(value)
+			}
+			return res.Failure(common.RTError(*posStart, *posEnd, fmt.Sprintf("'%s' holds values in %s, not %s", nama, t.Print(), bukan), context))
+		}
+		if ordinal < lo || ordinal > hi {
+			return res.Failure(common.RTError(*posStart, *posEnd, fmt.Sprintf("%s is out of the range %s of '%s'", common.PrintValueInterpreter(value), t.Print(), nama), context))
+		}
+	}
+
+	return res.Success(common.Null{})
+}
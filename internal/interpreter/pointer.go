@@ -0,0 +1,99 @@
+package interpreter
+
+import (
+	"dap/internal/common"
+	"fmt"
+)
+
+// ambilPointer evaluates the pointer of p^ and reports a nil or disposed one.
+func (i *Interpreter) ambilPointer(node common.DerefNode, context *common.Context) (common.Pointer, *common.Error) {
+	res := &common.RTResult{}
+
+	value := res.Register(i.Visit(node.Pointer, context))
+	if res.ShouldReturn() {
+		return common.Pointer{}, res.Error
+	}
+
+	pointer, ok := value.(common.Pointer)
+	if !ok {
+		err := common.RTError(*node.Pos_Start, *node.Pos_End, fmt.Sprintf("'%s' is a %s, not a pointer", node.Pointer.Print(), common.NamaTipe(value)), context)
+		return common.Pointer{}, &err
+	}
+	if _, pesan := pointer.Isi(); pesan != "" {
+		err := common.RTError(*node.Pos_Start, *node.Pos_End, fmt.Sprintf("Cannot use '%s': '%s' %s", node.Print(), node.Pointer.Print(), pesan), context)
+		return common.Pointer{}, &err
+	}
+
+	return pointer, nil
+}
+
+func (i *Interpreter) VisitDerefNode(node common.Expr, context *common.Context) common.Value {
+	res := &common.RTResult{}
+
+	pointer, err := i.ambilPointer(node.(common.DerefNode), context)
+	if err != nil {
+		return res.Failure(*err)
+	}
+
+	value, _ := pointer.Isi()
+	return res.Success(value)
+}
+
+func (i *Interpreter) VisitDerefAssignNode(node common.Expr, context *common.Context) common.Value {
+	res := &common.RTResult{}
+	assignNode := node.(common.DerefAssignNode)
+
+	value := res.Register(i.Visit(assignNode.ValueNode, context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	return i.simpanDi(assignNode.Deref, value, assignNode.ValueNode, context)
+}
+
+func (i *Interpreter) VisitPointerTypeNode(node common.Expr, context *common.Context) common.Value {
+	return i.InitializeType(node, context)
+}
+
+// baru runs new(p): p gets a pointer to a new place holding the default value
+// of the type it points to. That type comes from the declaration of p, or
+// from the pointer p already holds when p wasn't declared, as for a parameter.
+func (i *Interpreter) baru(node common.CallNode, context *common.Context) common.Value {
+	res := &common.RTResult{}
+
+	if len(node.ArgNodes) != 1 {
+		return res.Failure(common.RTError(*node.Pos_Start, *node.Pos_end, fmt.Sprintf("new expects 1 argument, got %d", len(node.ArgNodes)), context))
+	}
+	target := node.ArgNodes[0]
+	switch target.(type) {
+	case common.VarAccessNode, common.ArrayIndexNode, common.MemberAccessNode, common.DerefNode:
+	default:
+		return res.Failure(common.RTError(*target.GetPosStart(), *target.GetPosEnd(), fmt.Sprintf("new needs a pointer variable, an element or a field to store into, not '%s'", target.Print()), context))
+	}
+
+	var tipe common.Expr
+	if pointer, ok := i.tipeDeklarasi(target, context).(common.PointerTypeNode); ok {
+		tipe = pointer.OfType
+	} else {
+		// A target that doesn't exist yet holds no pointer to go by.
+		lihat := &common.RTResult{}
+		if lama, ok := lihat.Register(i.Visit(target, context)).(common.Pointer); ok && lihat.Error == nil {
+			tipe = lama.Tipe
+		}
+	}
+	if tipe == nil {
+		return res.Failure(common.RTError(*target.GetPosStart(), *target.GetPosEnd(), fmt.Sprintf("new: '%s' isn't declared as a pointer", target.Print()), context))
+	}
+
+	value := res.Register(i.InitializeType(tipe, context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	res.Register(i.simpan(target, common.BuatPointer(tipe, value).Set_context(context), "new", context))
+	if res.ShouldReturn() {
+		return res
+	}
+
+	return res.Success(common.Null{})
+}
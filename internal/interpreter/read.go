@@ -0,0 +1,245 @@
+package interpreter
+
+import (
+	"dap/internal/common"
+	"dap/internal/lexer"
+	"fmt"
+)
+
+// tipeVariabel is the hidden symbol that keeps the dictionary type of a
+// variable, next to the "ApakahKonstant " one, so read knows what to parse.
+const tipeVariabel = "Tipe "
+
+// baca runs read and readln. Every argument is a place to store into: a
+// variable, an array element or a struct field, and what is read is parsed
+// according to its declared type. readln then skips the rest of the line, and
+// a string it reads takes that whole rest of the line. With a file first they
+// read from that file instead of the console.
+func (i *Interpreter) baca(node common.CallNode, perBaris bool, context *common.Context) common.Value {
+	res := &common.RTResult{}
+	fungsi := node.NodeToCall.Print()
+	barisHabis := false
+
+	masukan := common.Masukan
+	targets := node.ArgNodes
+	if f, ok := i.ambilBerkas(targets, context); ok {
+		r, err := f.Pembaca(fungsi)
+		if err != nil {
+			return res.Failure(common.RTError(*targets[0].GetPosStart(), *targets[0].GetPosEnd(), err.Error(), context))
+		}
+		if !f.ApakahText() {
+			return i.bacaElemen(f, targets[1:], perBaris, fungsi, context)
+		}
+		masukan = r
+		targets = targets[1:]
+	}
+
+	for _, arg := range targets {
+		tipe := i.tipeDeklarasi(arg, context)
+		switch tipe.(type) {
+		case common.ArrayTypeNode, common.ListTypeNode, common.StructTypeNode:
+			return res.Failure(common.RTError(*arg.GetPosStart(), *arg.GetPosEnd(), fmt.Sprintf("%s: cannot read a whole array, list or struct, read its elements one by one", fungsi), context))
+		}
+		namaTipe := namaTipeDasar(tipe)
+		switch t := tipe.(type) {
+		case common.EnumTypeNode:
+			return res.Failure(common.RTError(*arg.GetPosStart(), *arg.GetPosEnd(), fmt.Sprintf("%s: cannot read a value of the enumeration %s", fungsi, t.TypeTok.Value), context))
+		case common.SubrangeTypeNode:
+			low, _, err := i.batasSubrange(t, context)
+			if err != nil {
+				return res.Failure(*err)
+			}
+			if _, jenis, _ := common.NilaiOrdinal(low); jenis == "integer" || jenis == "character" {
+				namaTipe = jenis
+			} else {
+				return res.Failure(common.RTError(*arg.GetPosStart(), *arg.GetPosEnd(), fmt.Sprintf("%s: cannot read a value of the enumeration %s", fungsi, jenis), context))
+			}
+		}
+
+		var teks string
+		var err error
+		if perBaris && namaTipe == "string" {
+			teks, err = common.BacaSisaBaris(masukan)
+			barisHabis = true
+		} else {
+			teks, err = common.BacaKata(masukan)
+			barisHabis = false
+		}
+		if err != nil {
+			return res.Failure(common.RTError(*arg.GetPosStart(), *arg.GetPosEnd(), fmt.Sprintf("%s: no more input to read into '%s'", fungsi, arg.Print()), context))
+		}
+
+		value, pesan := common.UbahTeks(teks, namaTipe)
+		if pesan != "" {
+			return res.Failure(common.RTError(*arg.GetPosStart(), *arg.GetPosEnd(), fmt.Sprintf("%s: %q is not %s, reading '%s'", fungsi, teks, pesan, arg.Print()), context))
+		}
+
+		res.Register(i.simpan(arg, value.Set_context(context), fungsi, context))
+		if res.ShouldReturn() {
+			return res
+		}
+	}
+
+	if perBaris && !barisHabis {
+		common.BacaSisaBaris(masukan)
+	}
+
+	return res.Success(common.Null{})
+}
+
+// ambilBerkas reports whether the first argument of read is a file rather
+// than a place to read into.
+func (i *Interpreter) ambilBerkas(args []common.Expr, context *common.Context) (common.File, bool) {
+	if len(args) == 0 {
+		return common.File{}, false
+	}
+	switch args[0].(type) {
+	case common.VarAccessNode, common.ArrayIndexNode, common.MemberAccessNode:
+	default:
+		return common.File{}, false
+	}
+
+	// A target that doesn't exist yet isn't a file; read reports it later.
+	res := &common.RTResult{}
+	value := res.Register(i.Visit(args[0], context))
+	if res.Error != nil {
+		return common.File{}, false
+	}
+	f, ok := value.(common.File)
+	return f, ok
+}
+
+// bacaElemen is read(f, ...) for a file of T: each target gets the next
+// element of the file.
+func (i *Interpreter) bacaElemen(f common.File, targets []common.Expr, perBaris bool, fungsi string, context *common.Context) common.Value {
+	res := &common.RTResult{}
+
+	if perBaris {
+		return res.Failure(common.RTError(*targets[0].GetPosStart(), *targets[0].GetPosEnd(), fmt.Sprintf("%s is for text files, use read for a file of %s", fungsi, f.Elemen.Print()), context))
+	}
+
+	for _, arg := range targets {
+		value, err := f.BacaElemen(fungsi)
+		if err != nil {
+			return res.Failure(common.RTError(*arg.GetPosStart(), *arg.GetPosEnd(), err.Error(), context))
+		}
+
+		res.Register(i.simpan(arg, value.Set_context(context), fungsi, context))
+		if res.ShouldReturn() {
+			return res
+		}
+	}
+
+	return res.Success(common.Null{})
+}
+
+// tipeElemen resolves the element type of a file, down to the fields of a
+// struct, and returns it as a Type. A file only holds simple values and
+// structs of them.
+func (i *Interpreter) tipeElemen(tipe common.Expr, context *common.Context) common.Value {
+	res := &common.RTResult{}
+
+	switch t := i.resolveTipe(tipe, context).(type) {
+	case common.VarAccessNode:
+		if namaTipeDasar(t) != "" {
+			return res.Success(common.Type{Definition: t})
+		}
+		return res.Failure(common.RTError(*tipe.GetPosStart(), *tipe.GetPosEnd(), fmt.Sprintf("Unknown type '%s'", t.VarNameTok.Value), context))
+	case common.StructTypeNode:
+		fields := make([]common.VarAssignNode, len(t.Fields))
+		for idx, field := range t.Fields {
+			fieldType := res.Register(i.tipeElemen(field.ValueNode, context))
+			if res.ShouldReturn() {
+				return res
+			}
+			field.ValueNode = fieldType.(common.Type).Definition
+			fields[idx] = field
+		}
+		t.Fields = fields
+		return res.Success(common.Type{Definition: t})
+	}
+
+	return res.Failure(common.RTError(*tipe.GetPosStart(), *tipe.GetPosEnd(), fmt.Sprintf("a file can't hold %s, only simple values and structs", tipe.Print()), context))
+}
+
+// simpan stores a value that was read into its target.
+func (i *Interpreter) simpan(target common.Expr, value common.Value, fungsi string, context *common.Context) common.Value {
+	res := &common.RTResult{}
+
+	switch t := target.(type) {
+	case common.VarAccessNode:
+		return i.GantiVariable(t.VarNameTok.Value, value, context, false, t.Pos_Start, t.Pos_end)
+	case common.ArrayIndexNode, common.MemberAccessNode, common.DerefNode:
+		return i.simpanDi(t, value, t, context)
+	}
+
+	return res.Failure(common.RTError(*target.GetPosStart(), *target.GetPosEnd(), fmt.Sprintf("%s needs a variable, an array element or a field to store into", fungsi), context))
+}
+
+// tipeDeklarasi finds the declared type of a read target: the dictionary type
+// of a variable, the element type of its array or list or the type of its field. It
+// is nil when nothing was declared, e.g. for function parameters.
+func (i *Interpreter) tipeDeklarasi(node common.Expr, context *common.Context) common.Expr {
+	switch n := node.(type) {
+	case common.VarAccessNode:
+		if tipe, ok := context.Symbol_Table.Get(tipeVariabel + n.VarNameTok.Value).(common.Type); ok {
+			return i.resolveTipe(tipe.Definition, context)
+		}
+	case common.ArrayIndexNode:
+		switch tipe := i.tipeDeklarasi(n.Left, context).(type) {
+		case common.ArrayTypeNode:
+			return i.resolveTipe(tipe.OfType, context)
+		case common.ListTypeNode:
+			return i.resolveTipe(tipe.OfType, context)
+		case common.VarAccessNode:
+			if tipe.VarNameTok.Value == "string" {
+				return common.VarAccessNode{VarNameTok: lexer.Token{Value: "character"}}
+			}
+		}
+	case common.MemberAccessNode:
+		if tipe, ok := i.tipeDeklarasi(n.Object, context).(common.StructTypeNode); ok {
+			for _, field := range tipe.Fields {
+				if field.VarName.Value == n.MemberTok.Value {
+					return i.resolveTipe(field.ValueNode, context)
+				}
+			}
+		}
+	case common.DerefNode:
+		if tipe, ok := i.tipeDeklarasi(n.Pointer, context).(common.PointerTypeNode); ok {
+			return i.resolveTipe(tipe.OfType, context)
+		}
+	}
+
+	return nil
+}
+
+// resolveTipe follows type aliases and struct names to their definition.
+func (i *Interpreter) resolveTipe(tipe common.Expr, context *common.Context) common.Expr {
+	for {
+		if pr, ok := tipe.(*common.ParseResult); ok {
+			tipe = pr.Node
+			continue
+		}
+
+		nama, ok := tipe.(common.VarAccessNode)
+		if !ok || namaTipeDasar(nama) != "" {
+			return tipe
+		}
+
+		definisi, ok := context.Symbol_Table.Get(nama.VarNameTok.Value).(common.Type)
+		if !ok {
+			return tipe
+		}
+		tipe = definisi.Definition
+	}
+}
+
+func namaTipeDasar(tipe common.Expr) string {
+	if nama, ok := tipe.(common.VarAccessNode); ok {
+		switch nama.VarNameTok.Value {
+		case "integer", "real", "string", "character":
+			return nama.VarNameTok.Value
+		}
+	}
+	return ""
+}
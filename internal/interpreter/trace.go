@@ -0,0 +1,131 @@
+package interpreter
+
+import (
+	"dap/internal/common"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type BarisTrace struct {
+	Line   int
+	Values []string
+}
+
+// Trace records a trace table: the values of the dictionary variables after
+// every statement executed in a block.
+type Trace struct {
+	Variables   []string
+	SymbolTable *common.SymbolTable
+	Rows        []BarisTrace
+}
+
+// NewTrace prepares a trace for the variables declared in the dictionary of
+// the program. Constants are left out since they never change.
+func NewTrace(program common.Expr, symbolTable *common.SymbolTable) *Trace {
+	trace := &Trace{SymbolTable: symbolTable, Variables: make([]string, 0)}
+
+	list, ok := program.(common.ListNode)
+	if !ok {
+		return trace
+	}
+
+	for _, v := range list.ElementNode {
+		dictionary, ok := v.(common.DictionaryNode)
+		if !ok {
+			continue
+		}
+
+		for _, variable := range dictionary.VariableDiBuat {
+			if assign, ok := variable.(common.VarAssignNode); ok && !assign.ApakahConst {
+				trace.Variables = append(trace.Variables, assign.VarName.Value)
+			}
+		}
+	}
+
+	return trace
+}
+
+func (t *Trace) Catat(node common.Expr) {
+	switch node.(type) {
+	case nil, common.NullNode, common.FuncNode, common.ListNode, common.NumberNode, common.StringNode:
+		return
+	}
+
+	baris := BarisTrace{Line: node.GetPosStart().Ln + 1, Values: make([]string, len(t.Variables))}
+	for i, name := range t.Variables {
+		if value := t.SymbolTable.Get(name); value != nil {
+			baris.Values[i] = common.PrintValueInterpreter(value)
+		}
+	}
+
+	t.Rows = append(t.Rows, baris)
+}
+
+func (t *Trace) tabel() [][]string {
+	hasil := [][]string{append([]string{"Line"}, t.Variables...)}
+	for _, baris := range t.Rows {
+		hasil = append(hasil, append([]string{strconv.Itoa(baris.Line)}, baris.Values...))
+	}
+	return hasil
+}
+
+func (t *Trace) Text() string {
+	tabel := t.tabel()
+	lebar := make([]int, len(tabel[0]))
+	for _, baris := range tabel {
+		for i, v := range baris {
+			lebar[i] = max(lebar[i], len(v))
+		}
+	}
+
+	var hasil strings.Builder
+	for j, baris := range tabel {
+		for i, v := range baris {
+			if i > 0 {
+				hasil.WriteString(" | ")
+			}
+			fmt.Fprintf(&hasil, "%-*s", lebar[i], v)
+		}
+		hasil.WriteString("\n")
+
+		if j == 0 {
+			for i := range baris {
+				if i > 0 {
+					hasil.WriteString("-+-")
+				}
+				hasil.WriteString(strings.Repeat("-", lebar[i]))
+			}
+			hasil.WriteString("\n")
+		}
+	}
+
+	return hasil.String()
+}
+
+func (t *Trace) CSV() string {
+	var hasil strings.Builder
+	writer := csv.NewWriter(&hasil)
+	writer.WriteAll(t.tabel())
+	return hasil.String()
+}
+
+func (t *Trace) Markdown() string {
+	tabel := t.tabel()
+
+	var hasil strings.Builder
+	for j, baris := range tabel {
+		cells := make([]string, len(baris))
+		for i, v := range baris {
+			cells[i] = strings.ReplaceAll(v, "|", `\|`)
+		}
+		hasil.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+
+		if j == 0 {
+			hasil.WriteString(strings.Repeat("| --- ", len(baris)) + "|\n")
+		}
+	}
+
+	return hasil.String()
+}
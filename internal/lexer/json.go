@@ -0,0 +1,34 @@
+package lexer
+
+import "dap/tools"
+
+// PositionJSON is the JSON form of a position: 1-based line and column and
+// the 0-based byte offset into the source.
+func PositionJSON(pos *tools.Position) any {
+	if pos == nil {
+		return nil
+	}
+
+	return map[string]any{
+		"line":   pos.Ln + 1,
+		"column": pos.Col + 1,
+		"offset": pos.Idx,
+	}
+}
+
+func (token Token) JSON() map[string]any {
+	return map[string]any{
+		"kind":  TokenKindString(token.Kind),
+		"value": token.Value,
+		"start": PositionJSON(token.Pos_Start),
+		"end":   PositionJSON(token.Pos_End),
+	}
+}
+
+func TokensJSON(tokens []Token) []map[string]any {
+	hasil := make([]map[string]any, 0, len(tokens))
+	for _, token := range tokens {
+		hasil = append(hasil, token.JSON())
+	}
+	return hasil
+}
@@ -4,6 +4,7 @@ import (
 	"dap/tools"
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 type regexHandler func(lex *lexer, regex *regexp.Regexp)
@@ -16,15 +17,26 @@ type regexPattern struct {
 type lexer struct {
 	patterns        []regexPattern
 	Tokens          []Token
+	Comments        []Comment
 	Pos             *tools.Position
 	Source          string
 	newLinePos      []int
 	apakahAdaNewLine bool
 }
 
+// Comment is a `//` comment the lexer skipped, kept for tools that want to
+// carry the original comments over (translators, formatters).
+type Comment struct {
+	Text string
+	Pos  *tools.Position
+}
+
 func (lex *lexer) advanceN(n int) {
-	lex.Pos.AdvanceN(n)
-	// lex.Pos += n
+	// Walk it char by char so Ln follows the newlines being skipped over.
+	for _, c := range lex.Source[lex.Pos.Idx : lex.Pos.Idx+n] {
+		lex.Pos.Advance(string(c))
+		lex.Pos.Idx += len(string(c)) - 1
+	}
 }
 
 func (lex *lexer) push(token Token) {
@@ -51,6 +63,25 @@ func (lex *lexer) at_eof() bool {
 var RegexNewLine = regexp.MustCompile(`\n|;`)
 
 func Tokenize(source string, fileName string) ([]Token, error) {
+	lex, err := tokenize(source, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return lex.Tokens, nil
+}
+
+// Comments returns every `//` comment in the source, in order.
+func Comments(source string, fileName string) ([]Comment, error) {
+	lex, err := tokenize(source, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return lex.Comments, nil
+}
+
+func tokenize(source string, fileName string) (*lexer, error) {
 	if fileName == "" {
 		fileName = "<stdin>"
 	}
@@ -85,13 +116,13 @@ func Tokenize(source string, fileName string) ([]Token, error) {
 	}
 
 	lex.push(NewToken(EOF, "EOF", lex.Pos, nil))
-	return lex.Tokens, nil
+	return lex, nil
 }
 
 func defaultHandler(kind TokenKind, value string) regexHandler {
 	return func(lex *lexer, regex *regexp.Regexp) {
-		lex.advanceN(len(value))
 		lex.push(NewToken(kind, value, lex.Pos, nil))
+		lex.advanceN(len(value))
 	}
 }
 
@@ -107,11 +138,11 @@ func createLexer(source string, fn string) *lexer {
 		},
 		Tokens: make([]Token, 0),
 		patterns: []regexPattern{
-			{regexp.MustCompile(`\n|;`), defaultHandler(NEWLINE, "\n")},
+			{regexp.MustCompile(`\n|;`), newlineHandler},
 			{regexp.MustCompile(`[a-zA-Z_][a-zA-Z0-9_]*`), symbolHandler},
 			{regexp.MustCompile(`[0-9]+(\.[0-9]+)?`), numberHandler},
 			{regexp.MustCompile(`"([^"\\]*(?:\\.[^"\\]*)*)"`), stringHandler},
-			{regexp.MustCompile(`\/\/.*`), skipHandler},
+			{regexp.MustCompile(`\/\/.*`), commentHandler},
 			{regexp.MustCompile(`\s+`), skipHandler},
 			{regexp.MustCompile(`\[`), defaultHandler(OPEN_BRACKET, "[")},
 			{regexp.MustCompile(`\]`), defaultHandler(CLOSE_BRACKET, "]")},
@@ -153,6 +184,16 @@ func createLexer(source string, fn string) *lexer {
 	return lex
 }
 
+func newlineHandler(lex *lexer, regex *regexp.Regexp) {
+	defaultHandler(NEWLINE, "\n")(lex, regex)
+
+	// This newline is already a token, so the whitespace after it must not
+	// mark another one (a line starting at column 0 would get split otherwise).
+	for len(lex.newLinePos) > 0 && lex.Pos.Idx >= lex.newLinePos[0] {
+		lex.newLinePos = lex.newLinePos[1:]
+	}
+}
+
 func numberHandler(lex *lexer, regex *regexp.Regexp) {
 	match := regex.FindString(lex.remainder())
 	lex.push(NewToken(NUMBER, match, lex.Pos, nil))
@@ -169,6 +210,16 @@ func skipHandler(lex *lexer, regex *regexp.Regexp) {
 	}
 }
 
+func commentHandler(lex *lexer, regex *regexp.Regexp) {
+	match := regex.FindString(lex.remainder())
+	lex.Comments = append(lex.Comments, Comment{
+		Text: strings.TrimSpace(match[2:]),
+		Pos:  lex.Pos.Copy(),
+	})
+
+	skipHandler(lex, regex)
+}
+
 func stringHandler(lex *lexer, regex *regexp.Regexp) {
 	match := regex.FindStringIndex(lex.remainder())
 	stringLiteral := lex.remainder()[match[0]:match[1]]
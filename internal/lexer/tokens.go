@@ -76,18 +76,26 @@ const (
 	WHILE
 	FOR
 	TO
+	DOWNTO
 	STEP
 	DO
 	END
 	ENDWHILE
 	ENDFOR
+	ENDFOREACH
 	ENDIF
 	INTEGER
 	REAL
 	STRINGTYPE
+	CHARACTER
 	ARRAY
 	OF
 	TYPE
+	CASE
+	DEPEND
+	OTHERWISE
+	ENDCASE
+	ENDDEPEND
 )
 
 var reserved_lu map[string]TokenKind = map[string]TokenKind{
@@ -113,18 +121,26 @@ var reserved_lu map[string]TokenKind = map[string]TokenKind{
 	"while":      WHILE,
 	"for":        FOR,
 	"to":         TO,
+	"downto":     DOWNTO,
 	"step":       STEP,
 	"do":         DO,
 	"end":        END,
 	"endwhile":   ENDWHILE,
 	"endfor":     ENDFOR,
+	"endforeach": ENDFOREACH,
 	"endif":      ENDIF,
 	"integer":    INTEGER,
 	"real":       REAL,
 	"string":     STRINGTYPE,
+	"character":  CHARACTER,
 	"array":      ARRAY,
 	"of":         OF,
 	"type":       TYPE,
+	"case":       CASE,
+	"depend":     DEPEND,
+	"otherwise":  OTHERWISE,
+	"endcase":    ENDCASE,
+	"enddepend":  ENDDEPEND,
 }
 
 type Token struct {
@@ -161,7 +177,9 @@ func NewToken(kind TokenKind, value string, Pos_start *tools.Position, Pos_end *
 	if Pos_start != nil {
 		tk.Pos_Start = Pos_start.Copy()
 		tk.Pos_End = Pos_start.Copy()
-		tk.Pos_End.Advance("")
+		if kind != EOF {
+			tk.Pos_End.AdvanceN(max(len(value), 1))
+		}
 	}
 
 	if Pos_end != nil {
@@ -293,6 +311,8 @@ func TokenKindString(kind TokenKind) string {
 		return "FOR"
 	case TO:
 		return "TO"
+	case DOWNTO:
+		return "DOWNTO"
 	case STEP:
 		return "STEP"
 	case DO:
@@ -301,6 +321,8 @@ func TokenKindString(kind TokenKind) string {
 		return "ENDIF"
 	case ENDWHILE:
 		return "ENDWHILE"
+	case ENDFOREACH:
+		return "ENDFOREACH"
 	case ENDFOR:
 		return "ENDFOR"
 	case END:
@@ -311,12 +333,24 @@ func TokenKindString(kind TokenKind) string {
 		return "REAL"
 	case STRINGTYPE:
 		return "STRING TYPE"
+	case CHARACTER:
+		return "CHARACTER"
 	case ARRAY:
 		return "ARRAY"
 	case OF:
 		return "OF"
 	case TYPE:
 		return "TYPE"
+	case CASE:
+		return "CASE"
+	case DEPEND:
+		return "DEPEND"
+	case OTHERWISE:
+		return "OTHERWISE"
+	case ENDCASE:
+		return "ENDCASE"
+	case ENDDEPEND:
+		return "ENDDEPEND"
 	default:
 		return "UNKNOWN"
 	}
@@ -0,0 +1,179 @@
+package parser
+
+import (
+	"dap/internal/common"
+	"dap/internal/lexer"
+	"dap/tools"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ambilKonstan looks up a name that can't be assigned: a constant from the
+// dictionary or a value of an enumeration, with the value it stands for.
+func (p *parser) ambilKonstan(nama string) (nilaiLabel, bool) {
+	if nilai, ok := p.konstan[nama]; ok {
+		return nilai, true
+	}
+	nilai, ok := p.nilaiEnum[nama]
+	return nilai, ok
+}
+
+// cekBukanKonstan stops an assignment to the name tok, whether by '<-', a
+// loop, read or new, when it names a constant.
+func (p *parser) cekBukanKonstan(tok lexer.Token) *common.Error {
+	if _, ok := p.ambilKonstan(tok.Value); !ok {
+		return nil
+	}
+
+	errorNya := common.InvalidSyntax(*tok.Pos_Start, *tok.Pos_End, fmt.Sprintf("Constant variable '%s' can not be assigned!", tok.Value))
+	if p.errorKonstan == nil {
+		p.errorKonstan = &errorNya
+	}
+	return &errorNya
+}
+
+// cekParameter stops a parameter named after a constant, which would stand
+// for two things inside its function.
+func (p *parser) cekParameter(tok lexer.Token) *common.Error {
+	if _, ok := p.ambilKonstan(tok.Value); !ok {
+		return nil
+	}
+
+	errorNya := common.InvalidSyntax(*tok.Pos_Start, *tok.Pos_End, fmt.Sprintf("'%s' is already defined as a constant", tok.Value))
+	if p.errorKonstan == nil {
+		p.errorKonstan = &errorNya
+	}
+	return &errorNya
+}
+
+// hitungKonstan works out the value of a constant expression while parsing:
+// literals, constants declared before it, true and false, joined by
+// arithmetic, comparisons, and, or and not. untuk names what needs the value,
+// for the error when node is something only known when the program runs.
+func (p *parser) hitungKonstan(node common.Expr, untuk string) (nilaiLabel, *common.Error) {
+	gagal := func(pesan string) (nilaiLabel, *common.Error) {
+		errorNya := common.InvalidSyntax(*node.GetPosStart(), *node.GetPosEnd(), pesan)
+		return nilaiLabel{}, &errorNya
+	}
+
+	switch n := node.(type) {
+	case *common.ParseResult:
+		return p.hitungKonstan(n.Node, untuk)
+	case common.NumberNode:
+		angka, _ := strconv.ParseFloat(n.Token.Value, 64)
+		return nilaiLabel{angka: angka}, nil
+	case common.StringNode:
+		teks, err := tools.UnescapeString(n.Token.Value[1 : len(n.Token.Value)-1])
+		if err != nil {
+			return gagal(err.Error())
+		}
+		return nilaiLabel{teks: true, string: teks}, nil
+	case common.VarAccessNode:
+		if nilai, ok := p.ambilKonstan(n.VarNameTok.Value); ok {
+			return nilai, nil
+		}
+		switch n.VarNameTok.Value {
+		case "true":
+			return nilaiLabel{angka: 1}, nil
+		case "false":
+			return nilaiLabel{angka: 0}, nil
+		}
+	case common.UnaryOpNode:
+		nilai, errorNya := p.hitungKonstan(n.Node, untuk)
+		if errorNya != nil {
+			return nilaiLabel{}, errorNya
+		}
+		if nilai.teks || nilai.enum != "" {
+			// not x has no position of its own.
+			errorNya := common.InvalidSyntax(*n.Node.GetPosStart(), *n.Node.GetPosEnd(), fmt.Sprintf("Illegal operation: cannot use '%s' on %s", n.Operator.Value, nilai.namaJenis()))
+			return nilaiLabel{}, &errorNya
+		}
+		switch n.Operator.Kind {
+		case lexer.DASH:
+			nilai.angka = -nilai.angka
+		case lexer.NOT:
+			nilai.angka = float64(tools.GetComparison(nilai.angka == 0))
+		}
+		return nilai, nil
+	case common.BinOpNode:
+		left, errorNya := p.hitungKonstan(n.Left, untuk)
+		if errorNya != nil {
+			return nilaiLabel{}, errorNya
+		}
+		right, errorNya := p.hitungKonstan(n.Right, untuk)
+		if errorNya != nil {
+			return nilaiLabel{}, errorNya
+		}
+
+		if hasil, ok := bandingkanKonstan(n.Operator.Kind, left, right); ok {
+			return hasil, nil
+		}
+		if !left.teks && left.enum == "" && !right.teks && right.enum == "" {
+			switch n.Operator.Kind {
+			case lexer.PLUS:
+				return nilaiLabel{angka: left.angka + right.angka}, nil
+			case lexer.DASH:
+				return nilaiLabel{angka: left.angka - right.angka}, nil
+			case lexer.STAR:
+				return nilaiLabel{angka: left.angka * right.angka}, nil
+			case lexer.SLASH:
+				if right.angka == 0 {
+					return gagal("Division by zero")
+				}
+				return nilaiLabel{angka: left.angka / right.angka}, nil
+			case lexer.POWER:
+				return nilaiLabel{angka: math.Pow(left.angka, right.angka)}, nil
+			case lexer.AND:
+				return nilaiLabel{angka: float64(tools.GetComparison(left.angka != 0 && right.angka != 0))}, nil
+			case lexer.OR:
+				return nilaiLabel{angka: float64(tools.GetComparison(left.angka != 0 || right.angka != 0))}, nil
+			}
+		}
+		if left.teks && right.teks && n.Operator.Kind == lexer.PLUS {
+			return nilaiLabel{teks: true, string: left.string + right.string}, nil
+		}
+		return gagal(fmt.Sprintf("Illegal operation: cannot use '%s' between %s and %s", n.Operator.Value, left.namaJenis(), right.namaJenis()))
+	}
+
+	return gagal(fmt.Sprintf("%s must be worked out before the program runs, from literals and constants, but '%s' isn't a constant", untuk, node.Print()))
+}
+
+// bandingkanKonstan compares two constants of one kind.
+func bandingkanKonstan(op lexer.TokenKind, a, b nilaiLabel) (nilaiLabel, bool) {
+	if !a.sejenis(b) {
+		return nilaiLabel{}, false
+	}
+
+	var hasil bool
+	switch op {
+	case lexer.EQUALS:
+		hasil = !a.kurangDari(b) && !b.kurangDari(a)
+	case lexer.NOT_EQUALS:
+		hasil = a.kurangDari(b) || b.kurangDari(a)
+	case lexer.LESS:
+		hasil = a.kurangDari(b)
+	case lexer.LESS_EQUALS:
+		hasil = !b.kurangDari(a)
+	case lexer.GREATER:
+		hasil = b.kurangDari(a)
+	case lexer.GREATER_EQUALS:
+		hasil = !a.kurangDari(b)
+	default:
+		return nilaiLabel{}, false
+	}
+	return nilaiLabel{angka: float64(tools.GetComparison(hasil))}, true
+}
+
+// batasArray works out a bound of an array, which must be a whole number.
+func (p *parser) batasArray(node common.Expr) (int, *common.Error) {
+	nilai, errorNya := p.hitungKonstan(node, "The bounds of an array")
+	if errorNya != nil {
+		return 0, errorNya
+	}
+	if nilai.teks || nilai.enum != "" || nilai.angka != math.Trunc(nilai.angka) {
+		errorNya := common.InvalidSyntax(*node.GetPosStart(), *node.GetPosEnd(), fmt.Sprintf("The bounds of an array must be whole numbers, '%s' isn't one", node.Print()))
+		return 0, &errorNya
+	}
+	return int(nilai.angka), nil
+}
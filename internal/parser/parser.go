@@ -6,6 +6,7 @@ import (
 	"dap/tools"
 	"fmt"
 	"slices"
+	"strconv"
 )
 
 type parser struct {
@@ -13,6 +14,19 @@ type parser struct {
 	hasEndProgram   bool
 	tok_index       int
 	apakahSatuBaris bool
+	// namaStruct holds the struct types declared so far, so that Point<...>
+	// and Point(...) are read as struct literals.
+	namaStruct map[string]bool
+	// nilaiEnum holds the values of the enumerations declared so far, so
+	// they can be case labels.
+	nilaiEnum map[string]nilaiLabel
+	// konstan holds the constants declared so far with their values, worked
+	// out while parsing, for array bounds, case labels and other constants.
+	konstan map[string]nilaiLabel
+	// errorKonstan is the first assignment to a constant found, or parameter
+	// named after one. Backtracking can't make it right, so statements
+	// reports it rather than a missing 'endprogram'.
+	errorKonstan *common.Error
 }
 
 func CreateParser(tokens []lexer.Token, ApakahSatuBaris bool) *parser {
@@ -20,6 +34,9 @@ func CreateParser(tokens []lexer.Token, ApakahSatuBaris bool) *parser {
 		tokens:          tokens,
 		tok_index:       -1,
 		apakahSatuBaris: ApakahSatuBaris,
+		namaStruct:      map[string]bool{},
+		nilaiEnum:       map[string]nilaiLabel{},
+		konstan:         map[string]nilaiLabel{},
 	}
 
 	p.advance()
@@ -34,11 +51,16 @@ func (p *parser) Parse(programName *string) common.Expr {
 		p.advance()
 	}
 
-	res.Register(p.DapatinProgram())
+	program := p.DapatinProgram().(*common.ParseResult)
+	res.Register(program)
 	if res.Error != nil && !p.apakahSatuBaris {
 		return res
 	}
 
+	if program.Error == nil && programName != nil {
+		*programName = program.ProgramName
+	}
+
 	hasil := p.statements().(*common.ParseResult)
 	if hasil.Error == nil {
 		if !p.hasEndProgram && p.currentToken().Kind != lexer.ENDPROGRAM && !p.apakahSatuBaris {
@@ -137,8 +159,34 @@ func (p *parser) parse_type() common.Expr {
 			return res
 		}
 
+		// array[a..b, c..d] of T is array[a..b] of array[c..d] of T.
+		rentang := [][2]common.Expr{{startExpr, endExpr}}
+		for p.currentToken().Kind == lexer.COMMA {
+			res.Register_Advancement()
+			p.advance()
+
+			low := res.Register(p.expr())
+			if res.Error != nil {
+				return res
+			}
+
+			if p.currentToken().Kind != lexer.DOT_DOT {
+				errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected '..'")
+				return res.Failure(&errorNya)
+			}
+
+			res.Register_Advancement()
+			p.advance()
+
+			high := res.Register(p.expr())
+			if res.Error != nil {
+				return res
+			}
+			rentang = append(rentang, [2]common.Expr{low, high})
+		}
+
 		if p.currentToken().Kind != lexer.CLOSE_BRACKET {
-			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected ']'")
+			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected ',' or ']'")
 			return res.Failure(&errorNya)
 		}
 
@@ -158,26 +206,207 @@ func (p *parser) parse_type() common.Expr {
 			return res
 		}
 
-		return res.Success(common.ArrayTypeNode{
-			StartNode: startExpr,
-			EndNode:   endExpr,
+		for idx := len(rentang) - 1; idx >= 0; idx-- {
+			start, errorNya := p.batasArray(rentang[idx][0])
+			if errorNya != nil {
+				return res.Failure(errorNya)
+			}
+			end, errorNya := p.batasArray(rentang[idx][1])
+			if errorNya != nil {
+				return res.Failure(errorNya)
+			}
+			if end < start-1 {
+				errorNya := common.InvalidSyntax(*rentang[idx][0].GetPosStart(), *rentang[idx][1].GetPosEnd(), "Array start index must be less than or equal to end index")
+				return res.Failure(&errorNya)
+			}
+
+			ofType = common.ArrayTypeNode{
+				StartNode: rentang[idx][0],
+				EndNode:   rentang[idx][1],
+				Start:     start,
+				End:       end,
+				OfType:    ofType,
+				Pos_Start: posStart,
+				Pos_End:   ofType.GetPosEnd(),
+			}
+		}
+		return res.Success(ofType)
+	}
+
+	// list, file and text aren't keywords, so they stay usable as names elsewhere.
+	if p.currentToken().Kind == lexer.IDENTIFIER && p.currentToken().Value == "list" && p.tokens[p.tok_index+1].Kind == lexer.OF {
+		posStart := p.currentToken().Pos_Start.Copy()
+		res.Register_Advancement()
+		p.advance()
+		res.Register_Advancement()
+		p.advance()
+
+		ofType := res.Register(p.parse_type())
+		if res.Error != nil {
+			return res
+		}
+
+		if _, ok := ofType.(common.FileTypeNode); ok {
+			errorNya := common.InvalidSyntax(*ofType.GetPosStart(), *ofType.GetPosEnd(), "A list can't hold files")
+			return res.Failure(&errorNya)
+		}
+
+		return res.Success(common.ListTypeNode{
 			OfType:    ofType,
 			Pos_Start: posStart,
 			Pos_End:   ofType.GetPosEnd(),
 		})
 	}
 
-	if p.currentToken().Kind == lexer.INTEGER || p.currentToken().Kind == lexer.REAL || p.currentToken().Kind == lexer.STRINGTYPE || p.currentToken().Kind == lexer.IDENTIFIER {
+	// ^T, or pointer to T; pointer isn't a keyword either.
+	if p.currentToken().Kind == lexer.POWER || (p.currentToken().Kind == lexer.IDENTIFIER && p.currentToken().Value == "pointer" && p.tokens[p.tok_index+1].Kind == lexer.TO) {
+		posStart := p.currentToken().Pos_Start.Copy()
+		if p.currentToken().Kind != lexer.POWER {
+			res.Register_Advancement()
+			p.advance()
+		}
+		res.Register_Advancement()
+		p.advance()
+
+		ofType := res.Register(p.parse_type())
+		if res.Error != nil {
+			return res
+		}
+
+		return res.Success(common.PointerTypeNode{
+			OfType:    ofType,
+			Pos_Start: posStart,
+			Pos_End:   ofType.GetPosEnd(),
+		})
+	}
+
+	if p.currentToken().Kind == lexer.IDENTIFIER && p.currentToken().Value == "text" {
+		tok := p.currentToken()
+		res.Register_Advancement()
+		p.advance()
+		return res.Success(common.FileTypeNode{Pos_Start: tok.Pos_Start, Pos_End: tok.Pos_End})
+	}
+
+	if p.currentToken().Kind == lexer.IDENTIFIER && p.currentToken().Value == "file" && p.tokens[p.tok_index+1].Kind == lexer.OF {
+		posStart := p.currentToken().Pos_Start.Copy()
+		res.Register_Advancement()
+		p.advance()
+		res.Register_Advancement()
+		p.advance()
+
+		ofType := res.Register(p.parse_type())
+		if res.Error != nil {
+			return res
+		}
+
+		switch ofType.(type) {
+		case common.FileTypeNode, common.ArrayTypeNode:
+			errorNya := common.InvalidSyntax(*ofType.GetPosStart(), *ofType.GetPosEnd(), "A file holds simple values or structs, not arrays or other files")
+			return res.Failure(&errorNya)
+		}
+
+		return res.Success(common.FileTypeNode{
+			OfType:    ofType,
+			Pos_Start: posStart,
+			Pos_End:   ofType.GetPosEnd(),
+		})
+	}
+
+	// A subrange starts with a constant, or with a name followed by '..'.
+	if kind := p.currentToken().Kind; kind == lexer.NUMBER || kind == lexer.DASH || kind == lexer.STRING || (kind == lexer.IDENTIFIER && p.tokens[p.tok_index+1].Kind == lexer.DOT_DOT) {
+		low := res.Register(p.arith_expr())
+		if res.Error != nil {
+			return res
+		}
+
+		if p.currentToken().Kind != lexer.DOT_DOT {
+			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected '..'")
+			return res.Failure(&errorNya)
+		}
+
+		res.Register_Advancement()
+		p.advance()
+
+		high := res.Register(p.arith_expr())
+		if res.Error != nil {
+			return res
+		}
+
+		for _, batas := range []common.Expr{low, high} {
+			if _, errorNya := p.hitungKonstan(batas, "The bounds of a subrange"); errorNya != nil {
+				return res.Failure(errorNya)
+			}
+		}
+
+		return res.Success(common.SubrangeTypeNode{
+			Low:       low,
+			High:      high,
+			Pos_Start: low.GetPosStart(),
+			Pos_End:   high.GetPosEnd(),
+		})
+	}
+
+	if p.currentToken().Kind == lexer.INTEGER || p.currentToken().Kind == lexer.REAL || p.currentToken().Kind == lexer.STRINGTYPE || p.currentToken().Kind == lexer.CHARACTER || p.currentToken().Kind == lexer.IDENTIFIER {
 		tok := p.currentToken()
 		res.Register_Advancement()
 		p.advance()
 		return res.Success(common.VarAccessNode{VarNameTok: tok, Pos_Start: tok.Pos_Start, Pos_end: tok.Pos_End})
 	}
 
-	errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected type (integer, real, string, or array)")
+	errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected type (integer, real, string, character, array, list of, file of, text or ^T)")
 	return res.Failure(&errorNya)
 }
 
+// enum_type parses the `(Mon, Tue, Wed)` of `type Day : (Mon, Tue, Wed)`.
+func (p *parser) enum_type(typeName lexer.Token) common.Expr {
+	res := &common.ParseResult{}
+	posStart := p.currentToken().Pos_Start.Copy()
+	res.Register_Advancement()
+	p.advance()
+
+	values := make([]lexer.Token, 0)
+	dipakai := make(map[string]bool)
+	for {
+		if p.currentToken().Kind != lexer.IDENTIFIER {
+			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected a name for a value of the enumeration")
+			return res.Failure(&errorNya)
+		}
+
+		tok := p.currentToken()
+		if dipakai[tok.Value] {
+			errorNya := common.InvalidSyntax(*tok.Pos_Start, *tok.Pos_End, fmt.Sprintf("'%s' appears twice in the enumeration", tok.Value))
+			return res.Failure(&errorNya)
+		}
+		dipakai[tok.Value] = true
+		values = append(values, tok)
+
+		res.Register_Advancement()
+		p.advance()
+
+		if p.currentToken().Kind == lexer.CLOSE_PAREN {
+			break
+		}
+		if p.currentToken().Kind != lexer.COMMA {
+			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected ',' or ')'")
+			return res.Failure(&errorNya)
+		}
+
+		res.Register_Advancement()
+		p.advance()
+	}
+
+	posEnd := p.currentToken().Pos_End.Copy()
+	res.Register_Advancement()
+	p.advance()
+
+	return res.Success(common.EnumTypeNode{
+		TypeTok:   typeName,
+		Values:    values,
+		Pos_Start: posStart,
+		Pos_End:   posEnd,
+	})
+}
+
 func (p *parser) dictionary_expr() common.Expr {
 	res := &common.ParseResult{}
 
@@ -224,6 +453,10 @@ func (p *parser) dictionary_expr() common.Expr {
 			}
 
 			for _, val := range ListVarNameToks {
+				if _, ok := p.ambilKonstan(val.Value); ok {
+					errorNya := common.InvalidSyntax(*val.Pos_Start, *val.Pos_End, fmt.Sprintf("'%s' is already defined as a constant", val.Value))
+					return res.Failure(&errorNya)
+				}
 				IsiNode = append(IsiNode, common.VarAssignNode{
 					VarName:   val,
 					ValueNode: tipeDataNode,
@@ -241,6 +474,10 @@ func (p *parser) dictionary_expr() common.Expr {
 			}
 
 			varName := p.currentToken()
+			if _, ok := p.ambilKonstan(varName.Value); ok {
+				errorNya := common.InvalidSyntax(*varName.Pos_Start, *varName.Pos_End, fmt.Sprintf("'%s' is already defined as a constant", varName.Value))
+				return res.Failure(&errorNya)
+			}
 			res.Register_Advancement()
 			p.advance()
 			// fmt.Fprintf(os.Stderr, "EXPR CHECK: %s (%s)\n", lexer.TokenKindString(p.currentToken().Kind), p.currentToken().Value)
@@ -257,6 +494,12 @@ func (p *parser) dictionary_expr() common.Expr {
 				return res
 			}
 
+			nilai, errorNya := p.hitungKonstan(expr, fmt.Sprintf("The value of the constant '%s'", varName.Value))
+			if errorNya != nil {
+				return res.Failure(errorNya)
+			}
+			p.konstan[varName.Value] = nilai
+
 			IsiNode = append(IsiNode, common.VarAssignNode{
 				VarName:     varName,
 				ValueNode:   expr,
@@ -280,10 +523,23 @@ func (p *parser) dictionary_expr() common.Expr {
 				// Type Alias: type Alias: TargetType
 				res.Register_Advancement()
 				p.advance()
-				targetType := res.Register(p.parse_type())
+				var targetType common.Expr
+				if p.currentToken().Kind == lexer.OPEN_PAREN {
+					targetType = res.Register(p.enum_type(typeName))
+				} else {
+					targetType = res.Register(p.parse_type())
+				}
 				if res.Error != nil {
 					return res
 				}
+				if enum, ok := targetType.(common.EnumTypeNode); ok {
+					for ordinal, tok := range enum.Values {
+						p.nilaiEnum[tok.Value] = nilaiLabel{enum: typeName.Value, angka: float64(ordinal)}
+					}
+				}
+				if target, ok := targetType.(common.VarAccessNode); ok && p.namaStruct[target.VarNameTok.Value] {
+					p.namaStruct[typeName.Value] = true
+				}
 				IsiNode = append(IsiNode, common.TypeAliasNode{
 					AliasName:  typeName,
 					TargetType: targetType,
@@ -297,7 +553,7 @@ func (p *parser) dictionary_expr() common.Expr {
 
 				var fields []common.VarAssignNode
 				for p.currentToken().Kind != lexer.GREATER {
-					if p.currentToken().Kind == lexer.NEWLINE {
+					if p.currentToken().Kind == lexer.NEWLINE || p.currentToken().Kind == lexer.COMMA {
 						res.Register_Advancement()
 						p.advance()
 						continue
@@ -335,6 +591,7 @@ func (p *parser) dictionary_expr() common.Expr {
 				res.Register_Advancement()
 				p.advance()
 
+				p.namaStruct[typeName.Value] = true
 				IsiNode = append(IsiNode, common.StructTypeNode{
 					StructName: typeName,
 					Fields:     fields,
@@ -418,8 +675,90 @@ func (p *parser) list_expr() common.Expr {
 	})
 }
 
+// struct_literal parses Point<x: 1, y: 2>, which sets the named fields, or
+// Point(1, 2), which gives every field in declaration order. The values
+// between '<' and '>' can't be comparisons unless they are in parentheses.
+func (p *parser) struct_literal() common.Expr {
+	res := &common.ParseResult{}
+	typeTok := p.currentToken()
+	res.Register_Advancement()
+	p.advance()
+
+	literal := common.StructLiteralNode{TypeTok: typeTok, Pos_Start: typeTok.Pos_Start.Copy()}
+
+	if p.currentToken().Kind == lexer.OPEN_PAREN {
+		literal.Positional = true
+		res.Register_Advancement()
+		p.advance()
+
+		for p.currentToken().Kind != lexer.CLOSE_PAREN {
+			if len(literal.Values) > 0 {
+				if p.currentToken().Kind != lexer.COMMA {
+					errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected ',' or ')'")
+					return res.Failure(&errorNya)
+				}
+				res.Register_Advancement()
+				p.advance()
+			}
+
+			value := res.Register(p.expr())
+			if res.Error != nil {
+				return res
+			}
+			literal.Values = append(literal.Values, value)
+		}
+	} else {
+		res.Register_Advancement()
+		p.advance()
+
+		for p.currentToken().Kind != lexer.GREATER {
+			if len(literal.Fields) > 0 {
+				if p.currentToken().Kind != lexer.COMMA {
+					errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected ',' or '>'")
+					return res.Failure(&errorNya)
+				}
+				res.Register_Advancement()
+				p.advance()
+			}
+
+			if p.currentToken().Kind != lexer.IDENTIFIER {
+				errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected field name")
+				return res.Failure(&errorNya)
+			}
+			fieldName := p.currentToken()
+			res.Register_Advancement()
+			p.advance()
+
+			if p.currentToken().Kind != lexer.COLON {
+				errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected ':' after field name")
+				return res.Failure(&errorNya)
+			}
+			res.Register_Advancement()
+			p.advance()
+
+			value := res.Register(p.arith_expr())
+			if res.Error != nil {
+				return res
+			}
+			literal.Fields = append(literal.Fields, common.VarAssignNode{
+				VarName:   fieldName,
+				ValueNode: value,
+				Pos_Start: fieldName.Pos_Start,
+				Pos_end:   value.GetPosEnd(),
+			})
+		}
+	}
+
+	literal.Pos_End = p.currentToken().Pos_End.Copy()
+	res.Register_Advancement()
+	p.advance()
+
+	return res.Success(literal)
+}
+
 func (p *parser) if_expr() common.Expr {
 	res := &common.ParseResult{}
+	pos_start := p.currentToken().Pos_Start.Copy()
 	all_cases := res.Register(p.if_expr_cases(lexer.IF))
 	if res.Error != nil {
 		return res
@@ -428,6 +767,8 @@ func (p *parser) if_expr() common.Expr {
 	return res.Success(common.IfNode{
 		Cases:     all_cases.(common.IfNode).Cases,
 		Else_case: all_cases.(common.IfNode).Else_case,
+		Pos_Start: pos_start,
+		Pos_end:   p.currentToken().Pos_Start.Copy(),
 	})
 }
 
@@ -577,126 +918,582 @@ func (p *parser) if_expr_cases(caseKeyword lexer.TokenKind) common.Expr {
 			},
 		})
 
-		all_cases := res.Register(p.if_expr_b_or_c())
+		all_cases := res.Register(p.if_expr_b_or_c())
+
+		else_case = *all_cases.(common.IfNode).Else_case
+		cases = append(cases, all_cases.(common.IfNode).Cases...)
+	}
+
+	return res.Success(common.IfNode{
+		Cases:     cases,
+		Else_case: &else_case,
+	})
+}
+
+func (p *parser) for_expr() common.Expr {
+	res := &common.ParseResult{}
+
+	if p.currentToken().Kind != lexer.FOR {
+		errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected 'for'")
+		return res.Failure(&errorNya)
+	}
+
+	res.Register_Advancement()
+	p.advance()
+
+	if p.currentToken().Kind != lexer.IDENTIFIER {
+		errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected identifier")
+		return res.Failure(&errorNya)
+	}
+
+	varName := p.currentToken()
+	if errorNya := p.cekBukanKonstan(varName); errorNya != nil {
+		return res.Failure(errorNya)
+	}
+	res.Register_Advancement()
+	p.advance()
+
+	if p.currentToken().Kind != lexer.ASSIGNMENT && p.currentToken().Kind != lexer.LEFT_ARROW {
+		errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected '=' or '<-'")
+		return res.Failure(&errorNya)
+	}
+
+	res.Register_Advancement()
+	p.advance()
+
+	startValue := res.Register(p.expr())
+	if res.Error != nil {
+		return res
+	}
+
+	if p.currentToken().Kind != lexer.TO && p.currentToken().Kind != lexer.DOWNTO {
+		errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected 'to' or 'downto'")
+		return res.Failure(&errorNya)
+	}
+
+	downto := p.currentToken().Kind == lexer.DOWNTO
+	res.Register_Advancement()
+	p.advance()
+
+	EndValue := res.Register(p.expr())
+	if res.Error != nil {
+		return res
+	}
+
+	var StepValue common.Expr = common.NullNode{}
+	if p.currentToken().Kind == lexer.STEP {
+		res.Register_Advancement()
+		p.advance()
+
+		StepValue = res.Register(p.expr())
+		if res.Error != nil {
+			return res
+		}
+	}
+
+	if p.currentToken().Kind != lexer.DO {
+		errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected 'do'")
+		return res.Failure(&errorNya)
+	}
+
+	res.Register_Advancement()
+	p.advance()
+
+	if p.currentToken().Kind == lexer.NEWLINE {
+		res.Register_Advancement()
+		p.advance()
+
+		body := res.Register(p.statements())
+		if res.Error != nil {
+			return res
+		}
+
+		if p.currentToken().Kind != lexer.END && p.currentToken().Kind != lexer.ENDFOR {
+			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected 'end' or 'endfor'")
+			return res.Failure(&errorNya)
+		}
+
+		res.Register_Advancement()
+		p.advance()
+
+		return res.Success(common.ForNode{
+			VarNameTok:       varName,
+			StartValueNode:   startValue,
+			EndValueNode:     EndValue,
+			StepValueNode:    StepValue,
+			Downto:           downto,
+			BodyNode:         body,
+			ShouldReturnNull: true,
+			Pos_Start:        varName.Pos_Start,
+			Pos_end:          body.GetPosEnd(),
+		})
+	}
+
+	IsiValue := res.Register(p.statement())
+	if res.Error != nil {
+		return res
+	}
+
+	return res.Success(common.ForNode{
+		VarNameTok:       varName,
+		StartValueNode:   startValue,
+		EndValueNode:     EndValue,
+		StepValueNode:    StepValue,
+		Downto:           downto,
+		BodyNode:         IsiValue,
+		ShouldReturnNull: false,
+		Pos_Start:        varName.Pos_Start,
+		Pos_end:          IsiValue.GetPosEnd(),
+	})
+}
+
+// foreach_expr parses `foreach x in c do` and `foreach i, x in c do`. `in`
+// isn't a keyword, so it stays usable as a name elsewhere.
+func (p *parser) foreach_expr() common.Expr {
+	res := &common.ParseResult{}
+
+	if p.currentToken().Kind != lexer.FOREACH {
+		errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected 'foreach'")
+		return res.Failure(&errorNya)
+	}
+	posStart := p.currentToken().Pos_Start.Copy()
+
+	res.Register_Advancement()
+	p.advance()
+
+	if p.currentToken().Kind != lexer.IDENTIFIER {
+		errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected identifier")
+		return res.Failure(&errorNya)
+	}
+
+	varName := p.currentToken()
+	res.Register_Advancement()
+	p.advance()
+
+	var indexTok *lexer.Token
+	if p.currentToken().Kind == lexer.COMMA {
+		res.Register_Advancement()
+		p.advance()
+
+		if p.currentToken().Kind != lexer.IDENTIFIER {
+			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected identifier")
+			return res.Failure(&errorNya)
+		}
+
+		index := varName
+		indexTok = &index
+		varName = p.currentToken()
+		res.Register_Advancement()
+		p.advance()
+	}
+
+	if errorNya := p.cekBukanKonstan(varName); errorNya != nil {
+		return res.Failure(errorNya)
+	}
+	if indexTok != nil {
+		if errorNya := p.cekBukanKonstan(*indexTok); errorNya != nil {
+			return res.Failure(errorNya)
+		}
+	}
+
+	if p.currentToken().Kind != lexer.IDENTIFIER || p.currentToken().Value != "in" {
+		errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected 'in'")
+		return res.Failure(&errorNya)
+	}
+
+	res.Register_Advancement()
+	p.advance()
+
+	iterable := res.Register(p.expr())
+	if res.Error != nil {
+		return res
+	}
+
+	if p.currentToken().Kind != lexer.DO {
+		errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected 'do'")
+		return res.Failure(&errorNya)
+	}
+
+	res.Register_Advancement()
+	p.advance()
+
+	if p.currentToken().Kind == lexer.NEWLINE {
+		res.Register_Advancement()
+		p.advance()
+
+		body := res.Register(p.statements())
+		if res.Error != nil {
+			return res
+		}
+
+		if p.currentToken().Kind != lexer.END && p.currentToken().Kind != lexer.ENDFOREACH {
+			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected 'end' or 'endforeach'")
+			return res.Failure(&errorNya)
+		}
+
+		res.Register_Advancement()
+		p.advance()
+
+		return res.Success(common.ForEachNode{
+			IndexTok:         indexTok,
+			VarNameTok:       varName,
+			IterableNode:     iterable,
+			BodyNode:         body,
+			ShouldReturnNull: true,
+			Pos_Start:        posStart,
+			Pos_end:          body.GetPosEnd(),
+		})
+	}
+
+	IsiNode := res.Register(p.statement())
+	if res.Error != nil {
+		return res
+	}
+
+	return res.Success(common.ForEachNode{
+		IndexTok:         indexTok,
+		VarNameTok:       varName,
+		IterableNode:     iterable,
+		BodyNode:         IsiNode,
+		ShouldReturnNull: false,
+		Pos_Start:        posStart,
+		Pos_end:          IsiNode.GetPosEnd(),
+	})
+}
+
+// case_expr parses `case x of ... endcase` and `depend on (x) ... enddepend`.
+// Every branch starts on a line of its own with its labels and a colon, and
+// its body is one statement on the same line or a block on the lines below.
+// `on` isn't a keyword, so it stays usable as a name elsewhere.
+func (p *parser) case_expr() common.Expr {
+	res := &common.ParseResult{}
+	posStart := p.currentToken().Pos_Start.Copy()
+
+	penutup := lexer.ENDCASE
+	if p.currentToken().Kind == lexer.DEPEND {
+		penutup = lexer.ENDDEPEND
+		res.Register_Advancement()
+		p.advance()
+
+		if p.currentToken().Kind != lexer.IDENTIFIER || p.currentToken().Value != "on" {
+			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected 'on'")
+			return res.Failure(&errorNya)
+		}
+	}
+	res.Register_Advancement()
+	p.advance()
+
+	subject := res.Register(p.expr())
+	if res.Error != nil {
+		return res
+	}
+
+	if penutup == lexer.ENDCASE {
+		if p.currentToken().Kind != lexer.OF {
+			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected 'of'")
+			return res.Failure(&errorNya)
+		}
+		res.Register_Advancement()
+		p.advance()
+	}
+
+	namaPenutup := "'endcase'"
+	if penutup == lexer.ENDDEPEND {
+		namaPenutup = "'enddepend'"
+	}
+
+	branches := make([]common.CaseBranch, 0)
+	var otherwise common.Expr
+	dipakai := make([]labelDipakai, 0)
+
+	for {
+		for p.currentToken().Kind == lexer.NEWLINE {
+			res.Register_Advancement()
+			p.advance()
+		}
+
+		if p.currentToken().Kind == penutup || p.currentToken().Kind == lexer.END {
+			break
+		}
+		if p.currentToken().Kind == lexer.EOF || p.currentToken().Kind == lexer.ENDPROGRAM {
+			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, fmt.Sprintf("Expected %s or 'end'", namaPenutup))
+			return res.Failure(&errorNya)
+		}
+		if otherwise != nil {
+			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "'otherwise' must be the last branch")
+			return res.Failure(&errorNya)
+		}
+
+		var labels []common.CaseLabel
+		if p.currentToken().Kind == lexer.OTHERWISE {
+			res.Register_Advancement()
+			p.advance()
+		} else {
+			var errorNya *common.Error
+			labels, errorNya = p.case_labels(res, &dipakai)
+			if errorNya != nil {
+				return res.Failure(errorNya)
+			}
+		}
+
+		if p.currentToken().Kind != lexer.COLON {
+			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected ':'")
+			return res.Failure(&errorNya)
+		}
+		res.Register_Advancement()
+		p.advance()
+
+		isi := res.Register(p.case_body(penutup))
+		if res.Error != nil {
+			return res
+		}
 
-		else_case = *all_cases.(common.IfNode).Else_case
-		cases = append(cases, all_cases.(common.IfNode).Cases...)
+		if labels == nil {
+			otherwise = isi
+		} else {
+			branches = append(branches, common.CaseBranch{Labels: labels, Isi: isi})
+		}
 	}
 
-	return res.Success(common.IfNode{
-		Cases:     cases,
-		Else_case: &else_case,
-	})
-}
-
-func (p *parser) for_expr() common.Expr {
-	res := &common.ParseResult{}
-
-	if p.currentToken().Kind != lexer.FOR {
-		errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected 'for'")
+	if len(branches) == 0 {
+		errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected at least one branch with labels")
 		return res.Failure(&errorNya)
 	}
 
+	posEnd := p.currentToken().Pos_End.Copy()
 	res.Register_Advancement()
 	p.advance()
 
-	if p.currentToken().Kind != lexer.IDENTIFIER {
-		errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected identifier")
-		return res.Failure(&errorNya)
-	}
+	return res.Success(common.CaseNode{
+		SubjectNode: subject,
+		Branches:    branches,
+		Otherwise:   otherwise,
+		Pos_Start:   posStart,
+		Pos_End:     posEnd,
+	})
+}
 
-	varName := p.currentToken()
-	res.Register_Advancement()
-	p.advance()
+// labelDipakai is a label already used in a case, kept to catch duplicates.
+type labelDipakai struct {
+	label     common.CaseLabel
+	low, high nilaiLabel
+}
 
-	if p.currentToken().Kind != lexer.ASSIGNMENT && p.currentToken().Kind != lexer.LEFT_ARROW {
-		errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected '=' or '<-'")
-		return res.Failure(&errorNya)
-	}
+// nilaiLabel is the value of a label or a constant, a number, a string or a
+// value of the enumeration enum, whose ordinal is in angka.
+type nilaiLabel struct {
+	teks   bool
+	enum   string
+	angka  float64
+	string string
+}
 
-	res.Register_Advancement()
-	p.advance()
+func (a nilaiLabel) sejenis(b nilaiLabel) bool {
+	return a.teks == b.teks && a.enum == b.enum
+}
 
-	startValue := res.Register(p.expr())
-	if res.Error != nil {
-		return res
+func (a nilaiLabel) namaJenis() string {
+	switch {
+	case a.teks:
+		return "string"
+	case a.enum != "":
+		return a.enum
 	}
+	return "number"
+}
 
-	if p.currentToken().Kind != lexer.TO {
-		errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected 'to'")
-		return res.Failure(&errorNya)
+func (a nilaiLabel) kurangDari(b nilaiLabel) bool {
+	if a.teks {
+		return a.string < b.string
 	}
+	return a.angka < b.angka
+}
 
-	res.Register_Advancement()
-	p.advance()
+// case_labels parses the labels of one branch: values and ranges separated by
+// commas. A label may be used only once in a case, and all of them must be
+// numbers or all strings.
+func (p *parser) case_labels(res *common.ParseResult, dipakai *[]labelDipakai) ([]common.CaseLabel, *common.Error) {
+	labels := make([]common.CaseLabel, 0)
 
-	EndValue := res.Register(p.expr())
-	if res.Error != nil {
-		return res
+	for {
+		posStart := p.currentToken().Pos_Start.Copy()
+		low, lowNilai, errorNya := p.case_literal(res)
+		if errorNya != nil {
+			return nil, errorNya
+		}
+		label := common.CaseLabel{Low: low}
+		highNilai := lowNilai
+
+		if p.currentToken().Kind == lexer.DOT_DOT {
+			res.Register_Advancement()
+			p.advance()
+
+			var high common.Expr
+			high, highNilai, errorNya = p.case_literal(res)
+			if errorNya != nil {
+				return nil, errorNya
+			}
+			label.High = high
+
+			if !lowNilai.sejenis(highNilai) {
+				errorNya := common.InvalidSyntax(*posStart, *high.GetPosEnd(), fmt.Sprintf("Both ends of '%s' must be numbers, strings or values of one enumeration", label.Print()))
+				return nil, &errorNya
+			}
+			if highNilai.kurangDari(lowNilai) {
+				errorNya := common.InvalidSyntax(*posStart, *high.GetPosEnd(), fmt.Sprintf("The range '%s' is empty, write the low end first", label.Print()))
+				return nil, &errorNya
+			}
+		}
+		posEnd := p.tokens[p.tok_index-1].Pos_End
+
+		for _, lama := range *dipakai {
+			if !lama.low.sejenis(lowNilai) {
+				errorNya := common.InvalidSyntax(*posStart, *posEnd, fmt.Sprintf("The labels of a case must all be numbers, all strings or all values of one enumeration, '%s' doesn't match '%s'", label.Print(), lama.label.Print()))
+				return nil, &errorNya
+			}
+			if !highNilai.kurangDari(lama.low) && !lama.high.kurangDari(lowNilai) {
+				pesan := fmt.Sprintf("Label '%s' overlaps '%s' used earlier in this case", label.Print(), lama.label.Print())
+				if label.High == nil && lama.label.High == nil {
+					pesan = fmt.Sprintf("Label '%s' is used twice in this case", label.Print())
+				}
+				errorNya := common.InvalidSyntax(*posStart, *posEnd, pesan)
+				return nil, &errorNya
+			}
+		}
+		*dipakai = append(*dipakai, labelDipakai{label: label, low: lowNilai, high: highNilai})
+		labels = append(labels, label)
+
+		if p.currentToken().Kind != lexer.COMMA {
+			break
+		}
+		res.Register_Advancement()
+		p.advance()
 	}
 
-	var StepValue common.Expr = common.NullNode{}
-	if p.currentToken().Kind == lexer.STEP {
+	return labels, nil
+}
+
+// case_literal parses one end of a label: a number, a negative number, a
+// string, a value of an enumeration or a constant.
+func (p *parser) case_literal(res *common.ParseResult) (common.Expr, nilaiLabel, *common.Error) {
+	tok := p.currentToken()
+
+	switch tok.Kind {
+	case lexer.NUMBER:
 		res.Register_Advancement()
 		p.advance()
+		angka, _ := strconv.ParseFloat(tok.Value, 64)
+		return common.NumberNode{Token: tok, Pos_Start: tok.Pos_Start, Pos_End: tok.Pos_End}, nilaiLabel{angka: angka}, nil
+	case lexer.DASH:
+		if angka := p.tokens[p.tok_index+1]; angka.Kind == lexer.NUMBER {
+			res.Register_Advancement()
+			p.advance()
+			res.Register_Advancement()
+			p.advance()
+			nilai, _ := strconv.ParseFloat(angka.Value, 64)
+			node := common.UnaryOpNode{
+				Operator:  tok,
+				Node:      common.NumberNode{Token: angka, Pos_Start: angka.Pos_Start, Pos_End: angka.Pos_End},
+				Pos_Start: tok.Pos_Start,
+				Pos_End:   angka.Pos_End,
+			}
+			return node, nilaiLabel{angka: -nilai}, nil
+		}
+	case lexer.STRING:
+		res.Register_Advancement()
+		p.advance()
+		teks, err := tools.UnescapeString(tok.Value[1 : len(tok.Value)-1])
+		if err != nil {
+			errorNya := common.InvalidSyntax(*tok.Pos_Start, *tok.Pos_End, err.Error())
+			return nil, nilaiLabel{}, &errorNya
+		}
+		return common.StringNode{Token: tok, Pos_Start: tok.Pos_Start, Pos_End: tok.Pos_End}, nilaiLabel{teks: true, string: teks}, nil
+	case lexer.IDENTIFIER:
+		if nilai, ok := p.ambilKonstan(tok.Value); ok {
+			res.Register_Advancement()
+			p.advance()
+			return common.VarAccessNode{VarNameTok: tok, Pos_Start: tok.Pos_Start, Pos_end: tok.Pos_End}, nilai, nil
+		}
+	}
 
-		StepValue = res.Register(p.expr())
-		if res.Error != nil {
-			return res
+	errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, fmt.Sprintf("Expected a number, a string, a value of an enumeration or a constant as a label, got %s", p.currentToken().Value))
+	return nil, nilaiLabel{}, &errorNya
+}
+
+// apakahLabel looks ahead for the start of the next branch: labels followed
+// by a colon, or otherwise.
+func (p *parser) apakahLabel() bool {
+	idx := p.tok_index
+	if p.tokens[idx].Kind == lexer.OTHERWISE {
+		return true
+	}
+
+	for {
+		for end := 0; end < 2; end++ {
+			if p.tokens[idx].Kind == lexer.DASH {
+				idx++
+			}
+			_, apakahKonstan := p.ambilKonstan(p.tokens[idx].Value)
+			if p.tokens[idx].Kind != lexer.NUMBER && p.tokens[idx].Kind != lexer.STRING && !(p.tokens[idx].Kind == lexer.IDENTIFIER && apakahKonstan) {
+				return false
+			}
+			idx++
+			if end == 1 || p.tokens[idx].Kind != lexer.DOT_DOT {
+				break
+			}
+			idx++
+		}
+
+		switch p.tokens[idx].Kind {
+		case lexer.COLON:
+			return true
+		case lexer.COMMA:
+			idx++
+		default:
+			return false
 		}
 	}
+}
 
-	if p.currentToken().Kind != lexer.DO {
-		errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected 'do'")
-		return res.Failure(&errorNya)
+// case_body parses the body of a branch after its colon: a statement on the
+// same line, or the lines below up to the next branch.
+func (p *parser) case_body(penutup lexer.TokenKind) common.Expr {
+	res := &common.ParseResult{}
+
+	if p.currentToken().Kind != lexer.NEWLINE {
+		return p.statement()
 	}
 
-	res.Register_Advancement()
-	p.advance()
+	posStart := p.currentToken().Pos_Start.Copy()
+	statements := make([]common.Expr, 0)
+	for {
+		for p.currentToken().Kind == lexer.NEWLINE {
+			res.Register_Advancement()
+			p.advance()
+		}
 
-	if p.currentToken().Kind == lexer.NEWLINE {
-		res.Register_Advancement()
-		p.advance()
+		kind := p.currentToken().Kind
+		if kind == penutup || kind == lexer.END || kind == lexer.EOF || kind == lexer.ENDPROGRAM || p.apakahLabel() {
+			break
+		}
 
-		body := res.Register(p.statements())
+		statement := res.Register(p.statement())
 		if res.Error != nil {
 			return res
 		}
+		statements = append(statements, statement)
 
-		if p.currentToken().Kind != lexer.END && p.currentToken().Kind != lexer.ENDFOR {
-			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected 'end' or 'endfor'")
+		if p.currentToken().Kind != lexer.NEWLINE {
+			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, fmt.Sprintf("Expected a new line, got %s", p.currentToken().Value))
 			return res.Failure(&errorNya)
 		}
-
-		res.Register_Advancement()
-		p.advance()
-
-		return res.Success(common.ForNode{
-			VarNameTok:       varName,
-			StartValueNode:   startValue,
-			EndValueNode:     EndValue,
-			StepValueNode:    StepValue,
-			BodyNode:         body,
-			ShouldReturnNull: true,
-			Pos_Start:        varName.Pos_Start,
-			Pos_end:          body.GetPosEnd(),
-		})
-	}
-
-	IsiValue := res.Register(p.statement())
-	if res.Error != nil {
-		return res
 	}
 
-	return res.Success(common.ForNode{
-		VarNameTok:       varName,
-		StartValueNode:   startValue,
-		EndValueNode:     EndValue,
-		StepValueNode:    StepValue,
-		BodyNode:         IsiValue,
-		ShouldReturnNull: false,
-		Pos_Start:        varName.Pos_Start,
-		Pos_end:          IsiValue.GetPosEnd(),
+	return res.Success(common.ListNode{
+		ElementNode: statements,
+		Blok:        true,
+		Pos_Start:   posStart,
+		Pos_End:     p.currentToken().Pos_Start.Copy(),
 	})
 }
 
@@ -894,6 +1691,12 @@ func (p *parser) function_def() common.Expr {
 			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected ')' or ','")
 			return res.Failure(&errorNya)
 		}
+
+		for _, arg := range ArgNameToks {
+			if errorNya := p.cekParameter(arg); errorNya != nil {
+				return res.Failure(errorNya)
+			}
+		}
 	} else {
 		if p.currentToken().Kind != lexer.CLOSE_PAREN {
 			errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.advance().Pos_End, "Expected ')' or identifier")
@@ -982,6 +1785,21 @@ func (p *parser) atom() common.Expr {
 			Pos_End:   tok.Pos_End,
 		})
 	case lexer.IDENTIFIER:
+		if berikut := p.tokens[p.tok_index+1].Kind; p.namaStruct[tok.Value] && (berikut == lexer.LESS || berikut == lexer.OPEN_PAREN) {
+			struct_literal := res.Register(p.struct_literal())
+			if res.Error != nil {
+				return res
+			}
+
+			return res.Success(struct_literal)
+		}
+
+		res.Register_Advancement()
+		p.advance()
+
+		return res.Success(common.VarAccessNode{VarNameTok: tok, Pos_Start: tok.Pos_Start, Pos_end: tok.Pos_End})
+	case lexer.NEW:
+		// new(p) is called like a built-in.
 		res.Register_Advancement()
 		p.advance()
 
@@ -1021,6 +1839,20 @@ func (p *parser) atom() common.Expr {
 		}
 
 		return res.Success(for_expr)
+	case lexer.CASE, lexer.DEPEND:
+		case_expr := res.Register(p.case_expr())
+		if res.Error != nil {
+			return res
+		}
+
+		return res.Success(case_expr)
+	case lexer.FOREACH:
+		foreach_expr := res.Register(p.foreach_expr())
+		if res.Error != nil {
+			return res
+		}
+
+		return res.Success(foreach_expr)
 	case lexer.WHILE:
 		while_expr := res.Register(p.while_expr())
 		if res.Error != nil {
@@ -1059,8 +1891,17 @@ func (p *parser) call() common.Expr {
 		return res
 	}
 
-	for p.currentToken().Kind == lexer.OPEN_PAREN || p.currentToken().Kind == lexer.OPEN_BRACKET || p.currentToken().Kind == lexer.DOT || tools.ApakahBuiltinFunction(atom.Print()) {
-		if p.currentToken().Kind == lexer.OPEN_PAREN || tools.ApakahBuiltinFunction(atom.Print()) {
+	for p.currentToken().Kind == lexer.OPEN_PAREN || p.currentToken().Kind == lexer.OPEN_BRACKET || p.currentToken().Kind == lexer.DOT || p.apakahDeref() || tools.ApakahBuiltinTanpaKurung(atom.Print()) {
+		if p.apakahDeref() {
+			res.Register_Advancement()
+			p.advance()
+
+			atom = common.DerefNode{
+				Pointer:   atom,
+				Pos_Start: atom.GetPosStart(),
+				Pos_End:   p.tokens[p.tok_index-1].Pos_End.Copy(),
+			}
+		} else if p.currentToken().Kind == lexer.OPEN_PAREN || tools.ApakahBuiltinTanpaKurung(atom.Print()) {
 			var argNodes []common.Expr
 			if p.currentToken().Kind == lexer.OPEN_PAREN {
 				res.Register_Advancement()
@@ -1070,7 +1911,7 @@ func (p *parser) call() common.Expr {
 					res.Register_Advancement()
 					p.advance()
 				} else {
-					argNodes = append(argNodes, res.Register(p.expr()))
+					argNodes = append(argNodes, res.Register(p.argument(atom)))
 					if res.Error != nil {
 						return res
 					}
@@ -1079,7 +1920,7 @@ func (p *parser) call() common.Expr {
 						res.Register_Advancement()
 						p.advance()
 
-						argNodes = append(argNodes, res.Register(p.expr()))
+						argNodes = append(argNodes, res.Register(p.argument(atom)))
 						if res.Error != nil {
 							return res
 						}
@@ -1093,14 +1934,14 @@ func (p *parser) call() common.Expr {
 					res.Register_Advancement()
 					p.advance()
 				}
-			} else if tools.ApakahBuiltinFunction(atom.Print()) {
+			} else if tools.ApakahBuiltinTanpaKurung(atom.Print()) {
 				// Builtin function without parens (e.g., write M[0][0])
 				// Try to parse expressions until end of line or keyword
 				for {
-					if p.currentToken().Kind == lexer.NEWLINE || p.currentToken().Kind == lexer.EOF || p.currentToken().Kind == lexer.ENDPROGRAM || p.currentToken().Kind == lexer.ENDWHILE || p.currentToken().Kind == lexer.ENDFOR || p.currentToken().Kind == lexer.ENDIF || p.currentToken().Kind == lexer.ELSE || p.currentToken().Kind == lexer.ELIF {
+					if p.currentToken().Kind == lexer.NEWLINE || p.currentToken().Kind == lexer.EOF || p.currentToken().Kind == lexer.ENDPROGRAM || p.currentToken().Kind == lexer.ENDWHILE || p.currentToken().Kind == lexer.ENDFOR || p.currentToken().Kind == lexer.ENDFOREACH || p.currentToken().Kind == lexer.ENDIF || p.currentToken().Kind == lexer.ELSE || p.currentToken().Kind == lexer.ELIF {
 						break
 					}
-					argNodes = append(argNodes, res.Register(p.expr()))
+					argNodes = append(argNodes, res.Register(p.argument(atom)))
 					if res.Error != nil {
 						return res
 					}
@@ -1113,6 +1954,18 @@ func (p *parser) call() common.Expr {
 				}
 			}
 
+			// read and new store into their arguments.
+			switch tools.SemuaBuiltInFunction[atom.Print()] {
+			case "Input", "ReadLn", "New":
+				for _, arg := range argNodes {
+					if access, ok := arg.(common.VarAccessNode); ok {
+						if errorNya := p.cekBukanKonstan(access.VarNameTok); errorNya != nil {
+							return res.Failure(errorNya)
+						}
+					}
+				}
+			}
+
 			atom = common.CallNode{
 				NodeToCall: atom,
 				ArgNodes:   argNodes,
@@ -1128,6 +1981,29 @@ func (p *parser) call() common.Expr {
 				return res
 			}
 
+			var highExpr common.Expr
+			if p.currentToken().Kind == lexer.DOT_DOT {
+				res.Register_Advancement()
+				p.advance()
+
+				highExpr = res.Register(p.expr())
+				if res.Error != nil {
+					return res
+				}
+			}
+
+			indices := []common.Expr{indexExpr}
+			for highExpr == nil && p.currentToken().Kind == lexer.COMMA {
+				res.Register_Advancement()
+				p.advance()
+
+				index := res.Register(p.expr())
+				if res.Error != nil {
+					return res
+				}
+				indices = append(indices, index)
+			}
+
 			if p.currentToken().Kind != lexer.CLOSE_BRACKET {
 				errorNya := common.InvalidSyntax(*p.currentToken().Pos_Start, *p.currentToken().Pos_End, "Expected ']'")
 				return res.Failure(&errorNya)
@@ -1136,11 +2012,36 @@ func (p *parser) call() common.Expr {
 			res.Register_Advancement()
 			p.advance()
 
-			atom = common.ArrayIndexNode{
-				Left:      atom,
-				Index:     indexExpr,
-				Pos_Start: atom.GetPosStart(),
-				Pos_End:   p.tokens[p.tok_index-1].Pos_End.Copy(),
+			if highExpr != nil {
+				atom = common.SliceNode{
+					Left:      atom,
+					Low:       indexExpr,
+					High:      highExpr,
+					Pos_Start: atom.GetPosStart(),
+					Pos_End:   p.tokens[p.tok_index-1].Pos_End.Copy(),
+				}
+				continue
+			}
+
+			if len(indices) == 1 {
+				atom = common.ArrayIndexNode{
+					Left:      atom,
+					Index:     indexExpr,
+					Pos_Start: atom.GetPosStart(),
+					Pos_End:   p.tokens[p.tok_index-1].Pos_End.Copy(),
+				}
+				continue
+			}
+
+			// m[i, j] is m[i][j], with each index knowing its dimension.
+			for idx, index := range indices {
+				atom = common.ArrayIndexNode{
+					Left:      atom,
+					Index:     index,
+					Dimension: idx + 1,
+					Pos_Start: atom.GetPosStart(),
+					Pos_End:   p.tokens[p.tok_index-1].Pos_End.Copy(),
+				}
 			}
 		} else if p.currentToken().Kind == lexer.DOT {
 			res.Register_Advancement()
@@ -1165,6 +2066,63 @@ func (p *parser) call() common.Expr {
 	return res.Success(atom)
 }
 
+// apakahDeref reports whether the '^' at the current token is p^ rather than
+// a power: it is when what follows can't start the exponent. A '-' starts one
+// only right after the '^', so p^ - 1 subtracts while 2^-1 is a power.
+func (p *parser) apakahDeref() bool {
+	if p.currentToken().Kind != lexer.POWER {
+		return false
+	}
+
+	berikut := p.tokens[p.tok_index+1]
+	switch berikut.Kind {
+	case lexer.NUMBER, lexer.STRING, lexer.IDENTIFIER, lexer.OPEN_PAREN, lexer.NOT, lexer.NEW:
+		return false
+	case lexer.DASH:
+		return berikut.Pos_Start.Idx != p.currentToken().Pos_Start.Idx+1
+	}
+	return true
+}
+
+// argument parses one argument of a call. The arguments of write may carry a
+// Pascal-style field width and number of decimals: write(x:8:2).
+func (p *parser) argument(callee common.Expr) common.Expr {
+	res := &common.ParseResult{}
+	value := res.Register(p.expr())
+	if res.Error != nil {
+		return res
+	}
+
+	if p.currentToken().Kind != lexer.COLON || !tools.ApakahWrite(callee.Print()) {
+		return res.Success(value)
+	}
+
+	res.Register_Advancement()
+	p.advance()
+	width := res.Register(p.arith_expr())
+	if res.Error != nil {
+		return res
+	}
+
+	var precision common.Expr
+	if p.currentToken().Kind == lexer.COLON {
+		res.Register_Advancement()
+		p.advance()
+		precision = res.Register(p.arith_expr())
+		if res.Error != nil {
+			return res
+		}
+	}
+
+	return res.Success(common.FormatNode{
+		Value:     value,
+		Width:     width,
+		Precision: precision,
+		Pos_Start: value.GetPosStart(),
+		Pos_End:   p.tokens[p.tok_index-1].Pos_End.Copy(),
+	})
+}
+
 func (p *parser) factor() common.Expr {
 	res := &common.ParseResult{}
 	tok := p.currentToken()
@@ -1260,11 +2218,21 @@ func (p *parser) statements() common.Expr {
 		}
 
 		// Use a local result for this statement to avoid reversing everything if it fails
+		awal := p.currentToken().Kind
 		statementRes := &common.ParseResult{}
-		statement := statementRes.Try_register(p.statement())
+		hasilStatement := p.statement()
+		statement := statementRes.Try_register(hasilStatement)
 		res.Register_Advancement() 
 		
 		if statement == nil {
+			// What opens with for, case and the like can only be a statement,
+			// so its own error says more than a missing 'endprogram' would.
+			if apakahAwalBlok(awal) {
+				return res.Failure(hasilStatement.(*common.ParseResult).Error)
+			}
+			if p.errorKonstan != nil {
+				return res.Failure(p.errorKonstan)
+			}
 			p.reverse(statementRes.ToReverseCount)
 			moreStatement = false
 			continue
@@ -1275,11 +2243,20 @@ func (p *parser) statements() common.Expr {
 
 	return res.Success(common.ListNode{
 		ElementNode: statements,
+		Blok:        true,
 		Pos_Start:   pos_start,
 		Pos_End:     p.currentToken().Pos_End.Copy(),
 	})
 }
 
+func apakahAwalBlok(kind lexer.TokenKind) bool {
+	switch kind {
+	case lexer.IF, lexer.FOR, lexer.FOREACH, lexer.WHILE, lexer.REPEAT, lexer.CASE, lexer.DEPEND:
+		return true
+	}
+	return false
+}
+
 func (p *parser) statement() common.Expr {
 	res := &common.ParseResult{}
 	pos_Start := p.currentToken().Pos_Start.Copy()
@@ -1363,10 +2340,11 @@ func (p *parser) expr() common.Expr {
 				apakahLeftArrow = true
 				break
 			}
-			if lookahead.Kind != lexer.IDENTIFIER && lookahead.Kind != lexer.OPEN_BRACKET && lookahead.Kind != lexer.CLOSE_BRACKET && lookahead.Kind != lexer.NUMBER && lookahead.Kind != lexer.DOT {
+			// Inside brackets anything goes, so xs[i + 1] and m[i, j] are targets too.
+			if bracketLevel == 0 && lookahead.Kind != lexer.IDENTIFIER && lookahead.Kind != lexer.OPEN_BRACKET && lookahead.Kind != lexer.CLOSE_BRACKET && lookahead.Kind != lexer.NUMBER && lookahead.Kind != lexer.DOT && lookahead.Kind != lexer.POWER {
 				break
 			}
-			if lookahead.Kind == lexer.NEWLINE {
+			if lookahead.Kind == lexer.NEWLINE || lookahead.Kind == lexer.EOF {
 				break
 			}
 			lookaheadCount++
@@ -1396,6 +2374,9 @@ func (p *parser) expr() common.Expr {
 
 			switch lhs := lhs.(type) {
 			case common.VarAccessNode:
+				if errorNya := p.cekBukanKonstan(lhs.VarNameTok); errorNya != nil {
+					return res.Failure(errorNya)
+				}
 				return res.Success(common.VarAssignNode{
 					VarName:   lhs.VarNameTok,
 					ValueNode: expr,
@@ -1416,6 +2397,13 @@ func (p *parser) expr() common.Expr {
 					Pos_Start:    lhs.GetPosStart(),
 					Pos_End:      expr.GetPosEnd(),
 				})
+			case common.DerefNode:
+				return res.Success(common.DerefAssignNode{
+					Deref:     lhs,
+					ValueNode: expr,
+					Pos_Start: lhs.GetPosStart(),
+					Pos_End:   expr.GetPosEnd(),
+				})
 			default:
 				errorNya := common.InvalidSyntax(*lhs.GetPosStart(), *lhs.GetPosEnd(), "Illegal assignment target")
 				return res.Failure(&errorNya)
@@ -0,0 +1,259 @@
+package similar
+
+import (
+	"dap/internal/common"
+	"dap/tools"
+)
+
+// Simbol is one element of a normalised program: identifiers, constants and
+// loop forms are all folded into the same few symbols, so renaming variables
+// or rewriting a while loop as a for loop doesn't change the sequence.
+type Simbol struct {
+	Nilai string
+	Line  int
+}
+
+var namaTetap = map[string]bool{
+	"integer": true,
+	"real":    true,
+	"string":  true,
+	"true":    true,
+	"false":   true,
+	"null":    true,
+	"nil":     true,
+}
+
+var dibalik = map[string]string{
+	">":  "<",
+	">=": "<=",
+}
+
+func Normalise(node common.Expr) []Simbol {
+	n := &normaliser{hasil: make([]Simbol, 0)}
+	n.walk(node, 1)
+	return n.hasil
+}
+
+type normaliser struct {
+	hasil []Simbol
+}
+
+func (n *normaliser) emit(nilai string, line int) {
+	n.hasil = append(n.hasil, Simbol{Nilai: nilai, Line: line})
+}
+
+func (n *normaliser) walk(node common.Expr, line int) {
+	if node == nil {
+		return
+	}
+
+	if pr, ok := node.(*common.ParseResult); ok {
+		n.walk(pr.Node, line)
+		return
+	}
+
+	if pos := node.GetPosStart(); pos != nil {
+		line = pos.Ln + 1
+	}
+
+	switch v := node.(type) {
+	case common.ListNode:
+		for _, element := range v.ElementNode {
+			n.walk(element, line)
+		}
+	case common.NumberNode, common.StringNode:
+		n.emit("CONST", line)
+	case common.NullNode:
+	case common.VarAccessNode:
+		n.identifier(v.VarNameTok.Value, line)
+	case common.VarAssignNode:
+		n.emit("ASSIGN", line)
+		n.emit("ID", line)
+		n.walk(v.ValueNode, line)
+	case common.BinOpNode:
+		left, right := v.Left, v.Right
+		operator := v.Operator.Value
+		if balik, ok := dibalik[operator]; ok {
+			operator, left, right = balik, right, left
+		}
+		n.emit(operator, line)
+		n.walk(left, line)
+		n.walk(right, line)
+	case common.UnaryOpNode:
+		n.emit("UNARY"+v.Operator.Value, line)
+		n.walk(v.Node, line)
+	case common.IfNode:
+		n.emit("IF", line)
+		for _, kasus := range v.Cases {
+			n.walk(kasus.Kondisi, line)
+			n.emit("THEN", line)
+			n.walk(kasus.Isi, line)
+		}
+		if v.Else_case != nil && v.Else_case.Isi != nil {
+			n.emit("ELSE", line)
+			n.walk(v.Else_case.Isi, line)
+		}
+		n.emit("ENDIF", line)
+	case common.CaseNode:
+		// Emitted like the if/elif chain it stands for.
+		n.emit("IF", line)
+		for _, cabang := range v.Branches {
+			for _, label := range cabang.Labels {
+				n.emit("==", line)
+				n.walk(v.SubjectNode, line)
+				n.walk(label.Low, line)
+				if label.High != nil {
+					n.walk(label.High, line)
+				}
+			}
+			n.emit("THEN", line)
+			n.walk(cabang.Isi, line)
+		}
+		if v.Otherwise != nil {
+			n.emit("ELSE", line)
+			n.walk(v.Otherwise, line)
+		}
+		n.emit("ENDIF", line)
+	case common.WhileNode:
+		n.emit("LOOP", line)
+		n.walk(v.KondisiNode, line)
+		n.walk(v.BodyNode, line)
+		n.emit("ENDLOOP", line)
+	case common.ForNode:
+		// Emitted as the equivalent while loop: i <- a; while i <= b ... i <- i + step.
+		n.emit("ASSIGN", line)
+		n.emit("ID", line)
+		n.walk(v.StartValueNode, line)
+		n.emit("LOOP", line)
+		n.emit("<=", line)
+		n.emit("ID", line)
+		n.walk(v.EndValueNode, line)
+		n.walk(v.BodyNode, line)
+		n.emit("ASSIGN", line)
+		n.emit("ID", line)
+		n.emit("+", line)
+		n.emit("ID", line)
+		n.emit("CONST", line)
+		n.emit("ENDLOOP", line)
+	case common.ForEachNode:
+		n.emit("LOOP", line)
+		n.emit("ID", line)
+		n.walk(v.IterableNode, line)
+		n.walk(v.BodyNode, line)
+		n.emit("ENDLOOP", line)
+	case common.RepeatNode:
+		n.emit("LOOP", line)
+		n.walk(v.BodyNode, line)
+		n.emit("UNTIL", line)
+		n.walk(v.KondisiNode, line)
+		n.emit("ENDLOOP", line)
+	case common.FuncNode:
+		n.emit("FUNCTION", line)
+		for range v.ArgNameToks {
+			n.emit("PARAM", line)
+		}
+		if v.ShouldAutoReturn {
+			n.emit("RETURN", line)
+		}
+		n.walk(v.BodyNode, line)
+		n.emit("ENDFUNCTION", line)
+	case common.CallNode:
+		n.emit("CALL", line)
+		n.walk(v.NodeToCall, line)
+		for _, arg := range v.ArgNodes {
+			n.walk(arg, line)
+		}
+	case common.ReturnNode:
+		n.emit("RETURN", line)
+		n.walk(v.NodeToReturn, line)
+	case common.ContinueNode:
+		n.emit("CONTINUE", line)
+	case common.BreakNode:
+		n.emit("BREAK", line)
+	case common.DictionaryNode:
+		for _, declaration := range v.VariableDiBuat {
+			n.walk(declaration, line)
+		}
+	case common.ArrayTypeNode:
+		n.emit("ARRAY", line)
+		n.walk(v.StartNode, line)
+		n.walk(v.EndNode, line)
+		n.walk(v.OfType, line)
+	case common.FileTypeNode:
+		n.emit("FILE", line)
+		if v.OfType != nil {
+			n.walk(v.OfType, line)
+		}
+	case common.ListTypeNode:
+		n.emit("LIST", line)
+		n.walk(v.OfType, line)
+	case common.EnumTypeNode:
+		n.emit("ENUM", line)
+	case common.SubrangeTypeNode:
+		n.emit("SUBRANGE", line)
+		n.walk(v.Low, line)
+		n.walk(v.High, line)
+	case common.PointerTypeNode:
+		n.emit("POINTER", line)
+		n.walk(v.OfType, line)
+	case common.DerefNode:
+		n.emit("DEREF", line)
+		n.walk(v.Pointer, line)
+	case common.ArrayIndexNode:
+		n.emit("INDEX", line)
+		n.walk(v.Left, line)
+		n.walk(v.Index, line)
+	case common.StructLiteralNode:
+		n.emit("RECORD", line)
+		for _, value := range v.Values {
+			n.walk(value, line)
+		}
+		for _, field := range v.Fields {
+			n.walk(field.ValueNode, line)
+		}
+	case common.SliceNode:
+		n.emit("SLICE", line)
+		n.walk(v.Left, line)
+		n.walk(v.Low, line)
+		n.walk(v.High, line)
+	case common.ArrayAssignNode:
+		n.emit("ASSIGN", line)
+		n.walk(v.ArrayAccess, line)
+		n.walk(v.ValueNode, line)
+	case common.MemberAccessNode:
+		n.emit("FIELD", line)
+		n.walk(v.Object, line)
+	case common.MemberAssignNode:
+		n.emit("ASSIGN", line)
+		n.walk(v.MemberAccess, line)
+		n.walk(v.ValueNode, line)
+	case common.DerefAssignNode:
+		n.emit("ASSIGN", line)
+		n.walk(v.Deref, line)
+		n.walk(v.ValueNode, line)
+	case common.FormatNode:
+		// Field widths only change how the output looks.
+		n.walk(v.Value, line)
+	case common.TypeAliasNode:
+		n.emit("TYPE", line)
+		n.walk(v.TargetType, line)
+	case common.StructTypeNode:
+		n.emit("STRUCT", line)
+		for _, field := range v.Fields {
+			n.walk(field, line)
+		}
+		n.emit("ENDSTRUCT", line)
+	default:
+		n.emit(node.Name(), line)
+	}
+}
+
+// identifier keeps the names of builtins and types, which say something about
+// the algorithm, and folds every user-chosen name into ID.
+func (n *normaliser) identifier(name string, line int) {
+	if _, ok := tools.SemuaBuiltInFunction[name]; ok || namaTetap[name] {
+		n.emit(name, line)
+		return
+	}
+	n.emit("ID", line)
+}
@@ -0,0 +1,157 @@
+package similar
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+type Rentang struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Cocok is a run of symbols found in both programs, reported by line range.
+type Cocok struct {
+	A      Rentang `json:"a"`
+	B      Rentang `json:"b"`
+	Length int     `json:"length"`
+}
+
+type Hasil struct {
+	A       string  `json:"a"`
+	B       string  `json:"b"`
+	Score   float64 `json:"score"`
+	Matches []Cocok `json:"matches"`
+}
+
+// Program is a submission ready to be compared.
+type Program struct {
+	FileName string
+	Simbol   []Simbol
+	gram     []uint64
+}
+
+func NewProgram(fileName string, simbol []Simbol, minMatch int) *Program {
+	p := &Program{FileName: fileName, Simbol: simbol}
+
+	for i := 0; i+minMatch <= len(simbol); i++ {
+		h := fnv.New64a()
+		for _, v := range simbol[i : i+minMatch] {
+			h.Write([]byte(v.Nilai))
+			h.Write([]byte{0})
+		}
+		p.gram = append(p.gram, h.Sum64())
+	}
+
+	return p
+}
+
+// Bandingkan scores two programs with greedy string tiling: the longest common
+// runs of at least minMatch symbols are taken first and never reused, and the
+// score is the share of both programs covered by them.
+func Bandingkan(a, b *Program, minMatch int) Hasil {
+	hasil := Hasil{A: a.FileName, B: b.FileName, Matches: make([]Cocok, 0)}
+	if len(a.Simbol) == 0 || len(b.Simbol) == 0 {
+		return hasil
+	}
+
+	indexB := map[uint64][]int{}
+	for j, g := range b.gram {
+		indexB[g] = append(indexB[g], j)
+	}
+
+	markedA := make([]bool, len(a.Simbol))
+	markedB := make([]bool, len(b.Simbol))
+	cocok := 0
+
+	for {
+		terpanjang := minMatch
+		kandidat := make([][2]int, 0)
+
+		for i, g := range a.gram {
+			if markedA[i] {
+				continue
+			}
+
+			for _, j := range indexB[g] {
+				panjang := 0
+				for i+panjang < len(a.Simbol) && j+panjang < len(b.Simbol) &&
+					!markedA[i+panjang] && !markedB[j+panjang] &&
+					a.Simbol[i+panjang].Nilai == b.Simbol[j+panjang].Nilai {
+					panjang++
+				}
+
+				if panjang > terpanjang {
+					terpanjang = panjang
+					kandidat = kandidat[:0]
+				}
+				if panjang == terpanjang {
+					kandidat = append(kandidat, [2]int{i, j})
+				}
+			}
+		}
+
+		if len(kandidat) == 0 {
+			break
+		}
+
+		for _, k := range kandidat {
+			i, j := k[0], k[1]
+			if apakahTertanda(markedA, i, terpanjang) || apakahTertanda(markedB, j, terpanjang) {
+				continue
+			}
+
+			for x := 0; x < terpanjang; x++ {
+				markedA[i+x] = true
+				markedB[j+x] = true
+			}
+			cocok += terpanjang
+
+			hasil.Matches = append(hasil.Matches, Cocok{
+				A:      rentang(a.Simbol[i : i+terpanjang]),
+				B:      rentang(b.Simbol[j : j+terpanjang]),
+				Length: terpanjang,
+			})
+		}
+	}
+
+	sort.Slice(hasil.Matches, func(x, y int) bool {
+		return hasil.Matches[x].A.Start < hasil.Matches[y].A.Start
+	})
+	hasil.Score = float64(2*cocok) / float64(len(a.Simbol)+len(b.Simbol))
+	return hasil
+}
+
+func apakahTertanda(marked []bool, start, panjang int) bool {
+	for x := start; x < start+panjang; x++ {
+		if marked[x] {
+			return true
+		}
+	}
+	return false
+}
+
+func rentang(simbol []Simbol) Rentang {
+	hasil := Rentang{Start: simbol[0].Line, End: simbol[0].Line}
+	for _, v := range simbol {
+		hasil.Start = min(hasil.Start, v.Line)
+		hasil.End = max(hasil.End, v.Line)
+	}
+	return hasil
+}
+
+// SemuaPasangan compares every pair of programs and ranks the pairs from the
+// most to the least similar.
+func SemuaPasangan(programs []*Program, minMatch int) []Hasil {
+	hasil := make([]Hasil, 0)
+	for i := 0; i < len(programs); i++ {
+		for j := i + 1; j < len(programs); j++ {
+			hasil = append(hasil, Bandingkan(programs[i], programs[j], minMatch))
+		}
+	}
+
+	sort.SliceStable(hasil, func(x, y int) bool {
+		return hasil[x].Score > hasil[y].Score
+	})
+	return hasil
+}
@@ -0,0 +1,33 @@
+// Package testutil runs DAP programs for the tests of other packages.
+package testutil
+
+import (
+	"dap/internal/common"
+	"dap/internal/interpreter"
+	"dap/internal/lexer"
+	"dap/internal/parser"
+	"testing"
+)
+
+// Jalankan runs a program the way the dap command does and returns its
+// global variables with the runtime error, if any. A program that doesn't
+// parse fails the test.
+func Jalankan(t testing.TB, source string) (*common.SymbolTable, *common.Error) {
+	t.Helper()
+
+	tokens, err := lexer.Tokenize(source, "<test>")
+	if err != nil {
+		t.Fatalf("lexer: %v", err)
+	}
+	nama := "<program>"
+	ast := parser.CreateParser(tokens, false).Parse(&nama).(*common.ParseResult)
+	if ast.Error != nil {
+		t.Fatalf("parser: %s", ast.Error.As_string())
+	}
+
+	tabel := &common.SymbolTable{Symbols: map[string]common.Value{}}
+	common.IsiGlobal(tabel)
+	inter := interpreter.Interpreter{}
+	hasil := inter.Visit(ast.Node, &common.Context{DisplayName: nama, Symbol_Table: tabel}).(*common.RTResult)
+	return tabel, hasil.Error
+}
@@ -0,0 +1,728 @@
+package translator
+
+import (
+	"dap/internal/common"
+	"dap/internal/lexer"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Strings become fixed char buffers in C; this is their capacity.
+const cStringSize = 256
+
+var cReserved = []string{
+	"auto", "break", "case", "char", "const", "continue", "default", "do", "double", "else", "enum", "extern", "float",
+	"for", "goto", "if", "int", "long", "register", "return", "short", "signed", "sizeof", "static", "struct", "switch",
+	"typedef", "union", "unsigned", "void", "volatile", "while",
+	"main", "printf", "scanf", "pow", "strcmp", "strcpy", "strlen", "snprintf", "memcpy", "STRING_SIZE",
+}
+
+type cEmitter struct {
+	penulis
+	prog        *program
+	fn          *function
+	pakaiMath   bool
+	pakaiString bool
+}
+
+// ToC translates a parsed DAP program into a single C99 source file. Strings
+// are mapped to char buffers, so only assignment, comparison, concatenation
+// into a variable, reading and writing are supported for them.
+func ToC(node common.Expr, programName string, comments []lexer.Comment) (string, *common.Error) {
+	prog, err := analyse(node, programName)
+	if err != nil {
+		return "", err
+	}
+
+	c := &cEmitter{prog: prog, penulis: penulis{tab: "    ", komentar: "//", comments: comments}}
+	body, err := c.emitProgram()
+	if err != nil {
+		return "", err
+	}
+
+	hasil := fmt.Sprintf("// Translated from DAP program %s\n#include <stdio.h>\n", prog.Name)
+	if c.pakaiMath {
+		hasil += "#include <math.h>\n"
+	}
+	if c.pakaiString {
+		hasil += "#include <string.h>\n"
+	}
+	hasil += fmt.Sprintf("\n#define STRING_SIZE %d\n", cStringSize)
+	return hasil + body, nil
+}
+
+func cName(name string) string {
+	if slices.Contains(cReserved, name) {
+		return name + "_"
+	}
+	return name
+}
+
+func (c *cEmitter) emitProgram() (string, *common.Error) {
+	for _, v := range c.prog.Globals {
+		if !v.Const {
+			continue
+		}
+		c.commentsUntil(startLine(v.Node))
+		value, _, err := c.expr(v.Value)
+		if err != nil {
+			return "", err
+		}
+		c.line("#define %s %s", cName(v.Name), value)
+	}
+
+	for _, s := range c.prog.Structs {
+		c.line("")
+		c.commentsUntil(startLine(s))
+		c.line("typedef struct {")
+		c.indent++
+		for _, f := range c.prog.structs[s.StructName.Value] {
+			if f.Tipe == nil {
+				return "", unsupported(f.Node, fmt.Sprintf("type '%s'", f.Node.Print()))
+			}
+			decl, err := c.declare(f.Tipe, cName(f.Name))
+			if err != nil {
+				return "", err
+			}
+			c.line("%s;", decl)
+		}
+		c.indent--
+		c.line("} %s;", cName(s.StructName.Value))
+	}
+
+	for _, alias := range c.prog.Aliases {
+		t := c.prog.resolve(alias.TargetType)
+		if t == nil {
+			return "", unsupported(alias, fmt.Sprintf("type '%s'", alias.AliasName.Value))
+		}
+		c.line("")
+		c.commentsUntil(startLine(alias))
+		decl, err := c.declare(t, cName(alias.AliasName.Value))
+		if err != nil {
+			return "", err
+		}
+		c.line("typedef %s;", decl)
+	}
+
+	c.line("")
+	for _, v := range c.prog.Globals {
+		if v.Const {
+			continue
+		}
+		c.commentsUntil(startLine(v.Node))
+		decl, err := c.declare(v.Tipe, cName(v.Name))
+		if err != nil {
+			return "", err
+		}
+		c.line("%s;", decl)
+	}
+
+	for _, fn := range c.prog.Functions {
+		signature, err := c.signature(fn)
+		if err != nil {
+			return "", err
+		}
+		c.line("%s;", signature)
+	}
+
+	for _, fn := range c.prog.Functions {
+		if err := c.emitFunction(fn); err != nil {
+			return "", err
+		}
+	}
+
+	c.line("")
+	c.line("int main(void) {")
+	c.indent++
+	if err := c.statements(c.prog.Main); err != nil {
+		return "", err
+	}
+	c.restComments()
+	c.line("return 0;")
+	c.indent--
+	c.line("}")
+
+	return c.buf.String(), nil
+}
+
+func (c *cEmitter) signature(fn *function) (string, *common.Error) {
+	params := make([]string, 0, len(fn.Params))
+	for _, p := range fn.Params {
+		decl, err := c.declare(p.Tipe, cParam(p))
+		if err != nil {
+			return "", err
+		}
+		params = append(params, decl)
+	}
+	if len(params) == 0 {
+		params = append(params, "void")
+	}
+
+	returnType := "void"
+	if fn.Return != nil {
+		if fn.Return.Kind == tArray || fn.Return.Kind == tString {
+			return "", unsupported(fn.Node, "returning arrays or strings from a function")
+		}
+		returnType, _ = c.declare(fn.Return, "")
+	}
+
+	return fmt.Sprintf("%s %s(%s)", strings.TrimSpace(returnType), cName(fn.Name), strings.Join(params, ", ")), nil
+}
+
+// cParam names a parameter in a function's signature. Arrays and strings
+// arrive as pointers in C, so they get their own name and are copied into a
+// local under the DAP name: DAP passes them by value.
+func cParam(p *variable) string {
+	if p.Tipe.Kind == tArray || p.Tipe.Kind == tString {
+		return cName(p.Name) + "_arg"
+	}
+	return cName(p.Name)
+}
+
+func (c *cEmitter) emitFunction(fn *function) *common.Error {
+	c.fn = fn
+	defer func() { c.fn = nil }()
+
+	signature, err := c.signature(fn)
+	if err != nil {
+		return err
+	}
+
+	c.line("")
+	c.commentsUntil(startLine(fn.Node))
+	c.line("%s {", signature)
+	c.indent++
+	for _, p := range fn.Params {
+		if nama := cParam(p); nama != cName(p.Name) {
+			decl, err := c.declare(p.Tipe, cName(p.Name))
+			if err != nil {
+				return err
+			}
+			c.pakaiString = true
+			c.line("%s;", decl)
+			c.line("memcpy(%s, %s, sizeof %s);", cName(p.Name), nama, cName(p.Name))
+		}
+	}
+	for _, v := range fn.Locals {
+		decl, err := c.declare(v.Tipe, cName(v.Name))
+		if err != nil {
+			return err
+		}
+		c.line("%s;", decl)
+	}
+
+	if fn.Node.ShouldAutoReturn {
+		value, _, err := c.expr(fn.Node.BodyNode)
+		if err != nil {
+			return err
+		}
+		c.line("return %s;", bare(value))
+	} else if err := c.statements(flatten(fn.Node.BodyNode)); err != nil {
+		return err
+	}
+	c.indent--
+	c.line("}")
+
+	return nil
+}
+
+// declare builds a C declarator, e.g. "int m[3][3]" for
+// array[1..3] of array[1..3] of integer named m.
+func (c *cEmitter) declare(t *tipe, name string) (string, *common.Error) {
+	switch t.Kind {
+	case tInteger, tBool:
+		return "int " + name, nil
+	case tReal:
+		return "double " + name, nil
+	case tString:
+		return fmt.Sprintf("char %s[STRING_SIZE]", name), nil
+	case tStruct:
+		return cName(t.Name) + " " + name, nil
+	case tArray:
+		start, _, err := c.expr(t.Start)
+		if err != nil {
+			return "", err
+		}
+		end, _, err := c.expr(t.End)
+		if err != nil {
+			return "", err
+		}
+		return c.declare(t.Elem, fmt.Sprintf("%s[%s]", name, ukuran(bare(start), bare(end))))
+	}
+
+	return "double " + name, nil
+}
+
+func (c *cEmitter) expr(node common.Expr) (string, *tipe, *common.Error) {
+	t := c.prog.typeOf(node, c.fn)
+
+	switch n := node.(type) {
+	case *common.ParseResult:
+		return c.expr(n.Node)
+	case common.NumberNode:
+		return n.Token.Value, t, nil
+	case common.StringNode:
+		return n.Token.Value, t, nil
+	case common.VarAccessNode:
+		switch n.VarNameTok.Value {
+		case "true":
+			return "1", t, nil
+		case "false":
+			return "0", t, nil
+		}
+		if c.prog.lookup(n.VarNameTok.Value, c.fn) == nil {
+			err := common.TranslationError(posOf(n.Pos_Start), posOf(n.Pos_end), fmt.Sprintf("'%s' is not defined", n.VarNameTok.Value))
+			return "", nil, &err
+		}
+		return cName(n.VarNameTok.Value), t, nil
+	case common.UnaryOpNode:
+		code, _, err := c.expr(n.Node)
+		if err != nil {
+			return "", nil, err
+		}
+		if strings.HasPrefix(code, "-") {
+			code = "(" + code + ")"
+		}
+		return n.Operator.Value + code, t, nil
+	case common.BinOpNode:
+		return c.binOp(n, t)
+	case common.StructLiteralNode:
+		fields, values, err := c.prog.isiStruct(n)
+		if err != nil {
+			return "", nil, err
+		}
+		parts := make([]string, 0, len(fields))
+		for idx, f := range fields {
+			if values[idx] == nil {
+				continue
+			}
+			if _, literal := values[idx].(common.StringNode); f.Tipe.Kind == tArray || (f.Tipe.Kind == tString && !literal) {
+				return "", nil, unsupported(values[idx], "an array or a string that isn't a literal in a struct literal")
+			}
+			value, _, err := c.expr(values[idx])
+			if err != nil {
+				return "", nil, err
+			}
+			parts = append(parts, fmt.Sprintf(".%s = %s", cName(f.Name), bare(value)))
+		}
+		if len(parts) == 0 {
+			parts = append(parts, "0")
+		}
+		return fmt.Sprintf("(%s){%s}", cName(t.Name), strings.Join(parts, ", ")), t, nil
+	case common.ArrayIndexNode:
+		if c.prog.typeOf(n.Left, c.fn).Kind == tString {
+			return "", nil, unsupported(n, "indexing a string")
+		}
+		left, leftTipe, err := c.expr(n.Left)
+		if err != nil {
+			return "", nil, err
+		}
+		index, indexTipe, err := c.expr(n.Index)
+		if err != nil {
+			return "", nil, err
+		}
+		if indexTipe.Kind == tReal {
+			index = fmt.Sprintf("(int)%s", index)
+		}
+		start := "0"
+		if leftTipe.Kind == tArray {
+			start, _, err = c.expr(leftTipe.Start)
+			if err != nil {
+				return "", nil, err
+			}
+		}
+		return fmt.Sprintf("%s[%s]", left, geser(bare(index), bare(start))), t, nil
+	case common.MemberAccessNode:
+		object, _, err := c.expr(n.Object)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s.%s", object, cName(n.MemberTok.Value)), t, nil
+	case common.CallNode:
+		callee, ok := n.NodeToCall.(common.VarAccessNode)
+		if !ok || c.prog.functions[callee.VarNameTok.Value] == nil {
+			return "", nil, unsupported(n, fmt.Sprintf("call to '%s'", n.NodeToCall.Print()))
+		}
+		args := make([]string, 0, len(n.ArgNodes))
+		for _, arg := range n.ArgNodes {
+			code, _, err := c.expr(arg)
+			if err != nil {
+				return "", nil, err
+			}
+			args = append(args, bare(code))
+		}
+		return fmt.Sprintf("%s(%s)", cName(callee.VarNameTok.Value), strings.Join(args, ", ")), t, nil
+	}
+
+	return "", nil, unsupported(node, node.Name())
+}
+
+func (c *cEmitter) binOp(n common.BinOpNode, t *tipe) (string, *tipe, *common.Error) {
+	left, leftTipe, err := c.expr(n.Left)
+	if err != nil {
+		return "", nil, err
+	}
+	right, rightTipe, err := c.expr(n.Right)
+	if err != nil {
+		return "", nil, err
+	}
+
+	op := n.Operator.Value
+	if leftTipe.isComposite() || rightTipe.isComposite() {
+		return "", nil, unsupported(n, fmt.Sprintf("'%s' on arrays or structs", op))
+	}
+	if leftTipe.Kind == tString || rightTipe.Kind == tString {
+		switch op {
+		case "==", "!=", "<", "<=", ">", ">=":
+			c.pakaiString = true
+			return fmt.Sprintf("(strcmp(%s, %s) %s 0)", left, right, op), t, nil
+		}
+		return "", nil, unsupported(n, fmt.Sprintf("'%s' on strings outside an assignment", op))
+	}
+
+	switch op {
+	case "^":
+		c.pakaiMath = true
+		return fmt.Sprintf("pow(%s, %s)", bare(left), bare(right)), t, nil
+	case "/":
+		if leftTipe.Kind != tReal && rightTipe.Kind != tReal {
+			left = "(double)" + left
+		}
+	}
+
+	return fmt.Sprintf("(%s %s %s)", left, op, right), t, nil
+}
+
+func (c *cEmitter) statements(statements []common.Expr) *common.Error {
+	for _, statement := range statements {
+		c.commentsUntil(startLine(statement))
+		if err := c.statement(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *cEmitter) assign(target string, targetTipe *tipe, valueNode common.Expr) *common.Error {
+	if targetTipe.Kind == tString {
+		c.pakaiString = true
+
+		// "a" + b + c becomes one snprintf into the target buffer.
+		parts := make([]common.Expr, 0)
+		var kumpul func(node common.Expr)
+		kumpul = func(node common.Expr) {
+			if bin, ok := node.(common.BinOpNode); ok && bin.Operator.Value == "+" {
+				kumpul(bin.Left)
+				kumpul(bin.Right)
+				return
+			}
+			parts = append(parts, node)
+		}
+		kumpul(valueNode)
+
+		if len(parts) == 1 {
+			value, _, err := c.expr(valueNode)
+			if err != nil {
+				return err
+			}
+			c.line("strcpy(%s, %s);", target, value)
+			return nil
+		}
+
+		formats := make([]string, 0, len(parts))
+		args := make([]string, 0, len(parts))
+		for _, part := range parts {
+			code, t, err := c.expr(part)
+			if err != nil {
+				return err
+			}
+			formats = append(formats, c.format(t))
+			args = append(args, bare(code))
+		}
+		c.line("snprintf(%s, sizeof %s, \"%s\", %s);", target, target, strings.Join(formats, ""), strings.Join(args, ", "))
+		return nil
+	}
+
+	if targetTipe.Kind == tArray {
+		return unsupported(valueNode, "assigning a whole array")
+	}
+
+	value, _, err := c.expr(valueNode)
+	if err != nil {
+		return err
+	}
+	c.line("%s = %s;", target, bare(value))
+	return nil
+}
+
+func (c *cEmitter) statement(node common.Expr) *common.Error {
+	switch n := node.(type) {
+	case nil, common.NullNode:
+		return nil
+	case *common.ParseResult:
+		return c.statement(n.Node)
+	case common.ListNode:
+		return c.statements(flatten(n))
+	case common.VarAssignNode:
+		target := c.prog.lookup(n.VarName.Value, c.fn)
+		return c.assign(cName(n.VarName.Value), target.Tipe, n.ValueNode)
+	case common.ArrayAssignNode:
+		target, targetTipe, err := c.expr(n.ArrayAccess)
+		if err != nil {
+			return err
+		}
+		return c.assign(target, targetTipe, n.ValueNode)
+	case common.MemberAssignNode:
+		target, targetTipe, err := c.expr(n.MemberAccess)
+		if err != nil {
+			return err
+		}
+		return c.assign(target, targetTipe, n.ValueNode)
+	case common.CallNode:
+		return c.call(n)
+	case common.IfNode:
+		for i, kasus := range n.Cases {
+			kondisi, _, err := c.expr(kasus.Kondisi)
+			if err != nil {
+				return err
+			}
+			if i == 0 {
+				c.line("if (%s) {", bare(kondisi))
+			} else {
+				c.indent--
+				c.line("} else if (%s) {", bare(kondisi))
+			}
+			c.indent++
+			if err := c.statement(kasus.Isi); err != nil {
+				return err
+			}
+		}
+		if n.Else_case != nil && n.Else_case.Isi != nil {
+			c.indent--
+			c.line("} else {")
+			c.indent++
+			if err := c.statement(n.Else_case.Isi); err != nil {
+				return err
+			}
+		}
+		c.indent--
+		c.line("}")
+	case common.WhileNode:
+		kondisi, _, err := c.expr(n.KondisiNode)
+		if err != nil {
+			return err
+		}
+		c.line("while (%s) {", bare(kondisi))
+		c.indent++
+		if err := c.statement(n.BodyNode); err != nil {
+			return err
+		}
+		c.indent--
+		c.line("}")
+	case common.RepeatNode:
+		c.line("do {")
+		c.indent++
+		if err := c.statement(n.BodyNode); err != nil {
+			return err
+		}
+		c.indent--
+		kondisi, _, err := c.expr(n.KondisiNode)
+		if err != nil {
+			return err
+		}
+		c.line("} while (!(%s));", bare(kondisi))
+	case common.ForNode:
+		return c.forLoop(n)
+	case common.ForEachNode:
+		loop, err := c.prog.lowerForeach(n, c.fn)
+		if err != nil {
+			return err
+		}
+		return c.forLoop(loop)
+	case common.CaseNode:
+		pilihan, err := lowerCase(n)
+		if err != nil {
+			return err
+		}
+		return c.statement(pilihan)
+	case common.ReturnNode:
+		if n.NodeToReturn == nil {
+			c.line("return;")
+			return nil
+		}
+		value, _, err := c.expr(n.NodeToReturn)
+		if err != nil {
+			return err
+		}
+		c.line("return %s;", bare(value))
+	case common.BreakNode:
+		c.line("break;")
+	case common.ContinueNode:
+		c.line("continue;")
+	default:
+		return unsupported(node, node.Name())
+	}
+
+	return nil
+}
+
+func (c *cEmitter) forLoop(n common.ForNode) *common.Error {
+	n = countDown(n)
+	nama := cName(n.VarNameTok.Value)
+	start, _, err := c.expr(n.StartValueNode)
+	if err != nil {
+		return err
+	}
+	end, _, err := c.expr(n.EndValueNode)
+	if err != nil {
+		return err
+	}
+
+	k, akhir, langkah := c.prog.namaLoop(n, c.fn)
+	k, akhir, langkah = cName(k), cName(akhir), cName(langkah)
+	deklarasi, err := c.declare(c.prog.lookup(n.VarNameTok.Value, c.fn).Tipe, k)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := n.StepValueNode.(common.NullNode); ok || n.StepValueNode == nil {
+		c.line("for (%s = %s, %s = %s; %s <= %s; %s++) {", deklarasi, bare(start), akhir, bare(end), k, akhir, k)
+	} else {
+		step, _, err := c.expr(n.StepValueNode)
+		if err != nil {
+			return err
+		}
+		switch {
+		case isNegativeLiteral(n.StepValueNode):
+			c.line("for (%s = %s, %s = %s; %s >= %s; %s += %s) {", deklarasi, bare(start), akhir, bare(end), k, akhir, k, step)
+		case isLiteral(n.StepValueNode):
+			c.line("for (%s = %s, %s = %s; %s <= %s; %s += %s) {", deklarasi, bare(start), akhir, bare(end), k, akhir, k, step)
+		default:
+			c.line("for (%s = %s, %s = %s, %s = %s; %s >= 0 ? %s <= %s : %s >= %s; %s += %s) {", deklarasi, bare(start), akhir, bare(end), langkah, bare(step), langkah, k, akhir, k, akhir, k, langkah)
+		}
+	}
+
+	c.indent++
+	c.line("%s = %s;", nama, k)
+	if err := c.statement(n.BodyNode); err != nil {
+		return err
+	}
+	c.indent--
+	c.line("}")
+
+	return nil
+}
+
+func (c *cEmitter) format(t *tipe) string {
+	switch t.Kind {
+	case tInteger, tBool:
+		return "%d"
+	case tString:
+		return "%s"
+	}
+	return "%g"
+}
+
+// writeArg gives the printf conversion and arguments for one argument of
+// write; x:8:2 becomes %*.*f with the width and precision passed along.
+func (c *cEmitter) writeArg(arg common.Expr) (string, []string, *common.Error) {
+	value := arg
+	format, ok := arg.(common.FormatNode)
+	if ok {
+		value = format.Value
+	}
+
+	code, t, err := c.expr(value)
+	if err != nil {
+		return "", nil, err
+	}
+	if t.Kind == tArray || t.Kind == tStruct {
+		return "", nil, unsupported(value, "writing a whole array or struct")
+	}
+	if !ok {
+		return c.format(t), []string{bare(code)}, nil
+	}
+
+	width, err := widthOf(format.Width, c.expr)
+	if err != nil {
+		return "", nil, err
+	}
+	if format.Precision == nil {
+		return "%*" + strings.TrimPrefix(c.format(t), "%"), []string{width, bare(code)}, nil
+	}
+
+	if t.Kind != tInteger && t.Kind != tReal {
+		return "", nil, unsupported(value, "writing a non-number with decimals")
+	}
+	precision, err := widthOf(format.Precision, c.expr)
+	if err != nil {
+		return "", nil, err
+	}
+	return "%*.*f", []string{width, precision, fmt.Sprintf("(double)(%s)", bare(code))}, nil
+}
+
+func (c *cEmitter) call(n common.CallNode) *common.Error {
+	callee, ok := n.NodeToCall.(common.VarAccessNode)
+	if !ok {
+		return unsupported(n, "calling a non-function value")
+	}
+
+	switch {
+	case isWrite(callee.VarNameTok.Value):
+		formats := make([]string, 0, len(n.ArgNodes))
+		args := make([]string, 0, len(n.ArgNodes))
+		for _, arg := range n.ArgNodes {
+			format, codes, err := c.writeArg(arg)
+			if err != nil {
+				return err
+			}
+			formats = append(formats, format)
+			args = append(args, codes...)
+		}
+		if isWriteln(callee.VarNameTok.Value) {
+			formats = append(formats, "\\n")
+		}
+		if len(args) == 0 {
+			c.line("printf(\"%s\");", strings.Join(formats, ""))
+		} else {
+			c.line("printf(\"%s\", %s);", strings.Join(formats, ""), strings.Join(args, ", "))
+		}
+	case isRead(callee.VarNameTok.Value):
+		for _, arg := range n.ArgNodes {
+			switch arg.(type) {
+			case common.VarAccessNode, common.ArrayIndexNode, common.MemberAccessNode:
+			default:
+				return unsupported(arg, "reading into an expression")
+			}
+
+			code, t, err := c.expr(arg)
+			if err != nil {
+				return err
+			}
+			switch t.Kind {
+			case tInteger:
+				c.line("scanf(\"%%d\", &%s);", code)
+			case tString:
+				if isReadln(callee.VarNameTok.Value) {
+					return unsupported(arg, "readln into a string")
+				}
+				c.line("scanf(\"%%255s\", %s);", code)
+			default:
+				c.line("scanf(\"%%lf\", &%s);", code)
+			}
+		}
+		if isReadln(callee.VarNameTok.Value) {
+			c.line("scanf(\"%%*[^\\n]\");")
+			c.line("getchar();")
+		}
+	default:
+		code, _, err := c.expr(n)
+		if err != nil {
+			return err
+		}
+		c.line("%s;", code)
+	}
+
+	return nil
+}
@@ -0,0 +1,971 @@
+package translator
+
+import (
+	"dap/internal/common"
+	"dap/internal/lexer"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const goPrelude = `var in = bufio.NewReader(os.Stdin)
+var out = bufio.NewWriter(os.Stdout)
+
+// Array is a DAP array[Start..End]: a slice indexed from Start instead of 0.
+type Array[T any] struct {
+	Start, End int
+	Elements   []T
+}
+
+func newArray[T any](start, end int, zero func() T) Array[T] {
+	elements := make([]T, end-start+1)
+	for i := range elements {
+		elements[i] = zero()
+	}
+	return Array[T]{Start: start, End: end, Elements: elements}
+}
+
+func (a Array[T]) At(i int) *T {
+	if i < a.Start || i > a.End {
+		out.Flush()
+		panic(fmt.Sprintf("Index %d out of bounds [%d..%d]", i, a.Start, a.End))
+	}
+	return &a.Elements[i-a.Start]
+}
+
+// clone copies an array or struct value, so changing the copy leaves the
+// original alone.
+func clone[T any](v T) T {
+	if c, ok := any(v).(interface{ clone() T }); ok {
+		return c.clone()
+	}
+	return v
+}
+
+func (a Array[T]) clone() Array[T] {
+	elements := make([]T, len(a.Elements))
+	for i, v := range a.Elements {
+		elements[i] = clone(v)
+	}
+	return Array[T]{Start: a.Start, End: a.End, Elements: elements}
+}
+
+func (a Array[T]) String() string {
+	parts := make([]string, len(a.Elements))
+	for i, v := range a.Elements {
+		parts[i] = show(v)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// scan reads the next value for read into v and, like the interpreter, stops
+// the program when the input has run out or doesn't fit.
+func scan(fungsi, nama string, v any) {
+	_, err := fmt.Fscan(in, v)
+	if err == nil {
+		return
+	}
+	out.Flush()
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		fmt.Fprintf(os.Stderr, "Runtime Error: %s: no more input to read into '%s'\n", fungsi, nama)
+	} else {
+		fmt.Fprintf(os.Stderr, "Runtime Error: %s: the input doesn't fit '%s'\n", fungsi, nama)
+	}
+	os.Exit(1)
+}
+
+// readLine reads the rest of the current line, like readln into a string.
+func readLine() string {
+	line, _ := in.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+func show(v any) string {
+	switch v := v.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.Itoa(b2i(v))
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(v)
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+`
+
+var goReserved = []string{
+	"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func", "go", "goto", "if",
+	"import", "interface", "map", "package", "range", "return", "select", "struct", "switch", "type", "var",
+	"int", "float64", "string", "bool", "len", "cap", "make", "new", "append", "copy", "panic", "print", "println", "nil", "true", "false",
+	"main", "in", "out", "show", "b2i", "clone", "newArray", "Array", "scan", "readLine", "math", "reflect", "strings", "strconv", "bufio", "io", "os", "fmt",
+}
+
+type goEmitter struct {
+	penulis
+	prog         *program
+	fn           *function
+	pakaiMath    bool
+	pakaiReflect bool
+}
+
+// ToGo translates a parsed DAP program into the source of a standalone Go
+// program that reads stdin and writes stdout the same way the interpreter does.
+func ToGo(node common.Expr, programName string, comments []lexer.Comment) (string, *common.Error) {
+	prog, err := analyse(node, programName)
+	if err != nil {
+		return "", err
+	}
+
+	g := &goEmitter{prog: prog, penulis: penulis{tab: "\t", komentar: "//", comments: comments}}
+	body, err := g.emitProgram()
+	if err != nil {
+		return "", err
+	}
+
+	imports := []string{"bufio", "fmt", "io", "os", "strconv", "strings"}
+	if g.pakaiMath {
+		imports = append(imports, "math")
+	}
+	if g.pakaiReflect {
+		imports = append(imports, "reflect")
+	}
+	slices.Sort(imports)
+
+	hasil := fmt.Sprintf("// Code generated by dap build from program %s. DO NOT EDIT.\n\npackage main\n\nimport (\n", prog.Name)
+	for _, v := range imports {
+		hasil += fmt.Sprintf("\t%q\n", v)
+	}
+	hasil += ")\n\n" + goPrelude + body
+	return hasil, nil
+}
+
+func goName(name string) string {
+	if slices.Contains(goReserved, name) {
+		return name + "_"
+	}
+	return name
+}
+
+func (g *goEmitter) emitProgram() (string, *common.Error) {
+	for _, alias := range g.prog.Aliases {
+		t := g.prog.resolve(alias.TargetType)
+		if t == nil {
+			return "", unsupported(alias, fmt.Sprintf("type '%s'", alias.AliasName.Value))
+		}
+		g.line("")
+		g.commentsUntil(startLine(alias))
+		g.line("type %s = %s", goName(alias.AliasName.Value), g.goType(t))
+	}
+
+	for _, s := range g.prog.Structs {
+		nama := goName(s.StructName.Value)
+		fields := g.prog.structs[s.StructName.Value]
+
+		g.line("")
+		g.commentsUntil(startLine(s))
+		g.line("type %s struct {", nama)
+		g.indent++
+		for _, f := range fields {
+			if f.Tipe == nil {
+				return "", unsupported(f.Node, fmt.Sprintf("type '%s'", f.Node.Print()))
+			}
+			g.line("%s %s", goName(f.Name), g.goType(f.Tipe))
+		}
+		g.indent--
+		g.line("}")
+
+		// Fields print in declaration order, like PrintValueInterpreter.
+		parts := make([]string, 0, len(fields))
+		for _, f := range fields {
+			parts = append(parts, fmt.Sprintf("%q + show(s.%s)", f.Name+": ", goName(f.Name)))
+		}
+		salin := make([]string, 0)
+		for _, f := range fields {
+			if f.Tipe.isComposite() {
+				salin = append(salin, goName(f.Name))
+			}
+		}
+		if len(salin) > 0 {
+			g.line("")
+			g.line("func (s %s) clone() %s {", nama, nama)
+			g.indent++
+			for _, f := range salin {
+				g.line("s.%s = clone(s.%s)", f, f)
+			}
+			g.line("return s")
+			g.indent--
+			g.line("}")
+		}
+
+		g.line("")
+		g.line("func (s %s) String() string {", nama)
+		g.indent++
+		g.line("return \"<\" + %s + \">\"", strings.Join(parts, " + \", \" + "))
+		g.indent--
+		g.line("}")
+	}
+
+	if len(g.prog.Globals) > 0 {
+		g.line("")
+	}
+	for _, v := range g.prog.Globals {
+		g.commentsUntil(startLine(v.Node))
+		if v.Const {
+			value, _, err := g.expr(v.Value)
+			if err != nil {
+				return "", err
+			}
+			g.line("const %s = %s", goName(v.Name), value)
+			continue
+		}
+
+		zero, err := g.zero(v.Tipe)
+		if err != nil {
+			return "", err
+		}
+		if v.Tipe.Kind == tArray || v.Tipe.Kind == tStruct {
+			g.line("var %s = %s", goName(v.Name), zero)
+		} else {
+			g.line("var %s %s", goName(v.Name), g.goType(v.Tipe))
+		}
+	}
+
+	for _, fn := range g.prog.Functions {
+		if err := g.emitFunction(fn); err != nil {
+			return "", err
+		}
+	}
+
+	g.line("")
+	g.line("func main() {")
+	g.indent++
+	g.line("defer out.Flush()")
+	if err := g.statements(g.prog.Main); err != nil {
+		return "", err
+	}
+	g.restComments()
+	g.indent--
+	g.line("}")
+
+	return g.buf.String(), nil
+}
+
+func (g *goEmitter) emitFunction(fn *function) *common.Error {
+	g.fn = fn
+	defer func() { g.fn = nil }()
+
+	params := make([]string, 0, len(fn.Params))
+	for _, p := range fn.Params {
+		params = append(params, fmt.Sprintf("%s %s", goName(p.Name), g.goType(p.Tipe)))
+	}
+
+	returnType := ""
+	if fn.Return != nil {
+		returnType = " " + g.goType(fn.Return)
+	}
+
+	g.line("")
+	g.commentsUntil(startLine(fn.Node))
+	g.line("func %s(%s)%s {", goName(fn.Name), strings.Join(params, ", "), returnType)
+	g.indent++
+	for _, p := range fn.Params {
+		if p.Tipe.isComposite() {
+			g.line("%s = clone(%s)", goName(p.Name), goName(p.Name))
+		}
+	}
+	for _, v := range fn.Locals {
+		zero, err := g.zero(v.Tipe)
+		if err != nil {
+			return err
+		}
+		g.line("var %s %s = %s", goName(v.Name), g.goType(v.Tipe), zero)
+		g.line("_ = %s", goName(v.Name))
+	}
+
+	if fn.Node.ShouldAutoReturn {
+		value, t, err := g.expr(fn.Node.BodyNode)
+		if err != nil {
+			return err
+		}
+		g.line("return %s", bare(g.convert(value, t, fn.Return)))
+	} else {
+		body := flatten(fn.Node.BodyNode)
+		if err := g.statements(body); err != nil {
+			return err
+		}
+
+		if fn.Return != nil {
+			if len(body) == 0 {
+				zero, _ := g.zero(fn.Return)
+				g.line("return %s", zero)
+			} else if _, ok := body[len(body)-1].(common.ReturnNode); !ok {
+				zero, _ := g.zero(fn.Return)
+				g.line("return %s", zero)
+			}
+		}
+	}
+	g.indent--
+	g.line("}")
+
+	return nil
+}
+
+func (g *goEmitter) goType(t *tipe) string {
+	switch t.Kind {
+	case tInteger:
+		return "int"
+	case tString:
+		return "string"
+	case tBool:
+		return "bool"
+	case tArray:
+		return fmt.Sprintf("Array[%s]", g.goType(t.Elem))
+	case tStruct:
+		return goName(t.Name)
+	}
+
+	return "float64"
+}
+
+func (g *goEmitter) zero(t *tipe) (string, *common.Error) {
+	switch t.Kind {
+	case tInteger, tReal:
+		return "0", nil
+	case tString:
+		return `""`, nil
+	case tBool:
+		return "false", nil
+	case tArray:
+		start, startTipe, err := g.expr(t.Start)
+		if err != nil {
+			return "", err
+		}
+		end, endTipe, err := g.expr(t.End)
+		if err != nil {
+			return "", err
+		}
+		elem, err := g.zero(t.Elem)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("newArray(%s, %s, func() %s { return %s })", g.convert(start, startTipe, tipeInteger), g.convert(end, endTipe, tipeInteger), g.goType(t.Elem), elem), nil
+	case tStruct:
+		parts := make([]string, 0)
+		for _, f := range g.prog.structs[t.Name] {
+			if f.Tipe.Kind != tArray && f.Tipe.Kind != tStruct {
+				continue
+			}
+			zero, err := g.zero(f.Tipe)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, fmt.Sprintf("%s: %s", goName(f.Name), zero))
+		}
+		return fmt.Sprintf("%s{%s}", goName(t.Name), strings.Join(parts, ", ")), nil
+	}
+
+	return "0", nil
+}
+
+// convert wraps code of type from so it can be used where type to is expected.
+func (g *goEmitter) convert(code string, from *tipe, to *tipe) string {
+	if to == nil || from.Kind == to.Kind {
+		return code
+	}
+
+	switch to.Kind {
+	case tInteger:
+		switch from.Kind {
+		case tReal:
+			return fmt.Sprintf("int(%s)", code)
+		case tBool:
+			return fmt.Sprintf("b2i(%s)", code)
+		}
+	case tReal:
+		switch from.Kind {
+		case tInteger:
+			return fmt.Sprintf("float64(%s)", code)
+		case tBool:
+			return fmt.Sprintf("float64(b2i(%s))", code)
+		}
+	case tBool:
+		if from.Kind == tInteger || from.Kind == tReal {
+			return fmt.Sprintf("(%s != 0)", code)
+		}
+	}
+
+	return code
+}
+
+// bare drops the outermost parentheses of a generated expression, for
+// places like conditions and assignments where they are just noise.
+func bare(code string) string {
+	if !strings.HasPrefix(code, "(") || !strings.HasSuffix(code, ")") {
+		return code
+	}
+
+	level := 0
+	for i, c := range code {
+		switch c {
+		case '(':
+			level++
+		case ')':
+			level--
+			if level == 0 && i != len(code)-1 {
+				return code
+			}
+		}
+	}
+
+	return code[1 : len(code)-1]
+}
+
+func (g *goEmitter) cond(node common.Expr) (string, *common.Error) {
+	code, t, err := g.expr(node)
+	if err != nil {
+		return "", err
+	}
+	if t.Kind == tString {
+		return fmt.Sprintf("(%s != \"\")", code), nil
+	}
+	return g.convert(code, t, tipeBool), nil
+}
+
+func (g *goEmitter) expr(node common.Expr) (string, *tipe, *common.Error) {
+	t := g.prog.typeOf(node, g.fn)
+
+	switch n := node.(type) {
+	case *common.ParseResult:
+		return g.expr(n.Node)
+	case common.NumberNode:
+		return n.Token.Value, t, nil
+	case common.StringNode:
+		return n.Token.Value, t, nil
+	case common.VarAccessNode:
+		switch n.VarNameTok.Value {
+		case "true", "false":
+			return n.VarNameTok.Value, t, nil
+		}
+		if g.prog.lookup(n.VarNameTok.Value, g.fn) == nil {
+			err := common.TranslationError(posOf(n.Pos_Start), posOf(n.Pos_end), fmt.Sprintf("'%s' is not defined", n.VarNameTok.Value))
+			return "", nil, &err
+		}
+		return goName(n.VarNameTok.Value), t, nil
+	case common.UnaryOpNode:
+		if n.Operator.Value == "!" {
+			code, err := g.cond(n.Node)
+			return "!" + code, t, err
+		}
+		code, _, err := g.expr(n.Node)
+		if err != nil {
+			return "", nil, err
+		}
+		if strings.HasPrefix(code, "-") {
+			code = "(" + code + ")"
+		}
+		return n.Operator.Value + code, t, nil
+	case common.BinOpNode:
+		return g.binOp(n, t)
+	case common.StructLiteralNode:
+		code, err := g.structLiteral(n)
+		return code, t, err
+	case common.ArrayIndexNode:
+		if g.prog.typeOf(n.Left, g.fn).Kind == tString {
+			left, _, err := g.expr(n.Left)
+			if err != nil {
+				return "", nil, err
+			}
+			index, indexTipe, err := g.expr(n.Index)
+			if err != nil {
+				return "", nil, err
+			}
+			return fmt.Sprintf("string([]rune(%s)[%s])", left, geser(bare(g.convert(index, indexTipe, tipeInteger)), "1")), t, nil
+		}
+		element, err := g.element(n)
+		if err != nil {
+			return "", nil, err
+		}
+		return "*" + element, t, nil
+	case common.MemberAccessNode:
+		var object string
+		var err *common.Error
+		if index, ok := n.Object.(common.ArrayIndexNode); ok {
+			object, err = g.element(index)
+		} else {
+			object, _, err = g.expr(n.Object)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s.%s", object, goName(n.MemberTok.Value)), t, nil
+	case common.CallNode:
+		callee, ok := n.NodeToCall.(common.VarAccessNode)
+		if !ok || g.prog.functions[callee.VarNameTok.Value] == nil {
+			return "", nil, unsupported(n, fmt.Sprintf("call to '%s'", n.NodeToCall.Print()))
+		}
+		target := g.prog.functions[callee.VarNameTok.Value]
+		if len(n.ArgNodes) != len(target.Params) {
+			err := common.TranslationError(posOf(n.GetPosStart()), posOf(n.GetPosEnd()), fmt.Sprintf("'%s' takes %d args, got %d", target.Name, len(target.Params), len(n.ArgNodes)))
+			return "", nil, &err
+		}
+		args := make([]string, 0, len(n.ArgNodes))
+		for i, arg := range n.ArgNodes {
+			code, argTipe, err := g.expr(arg)
+			if err != nil {
+				return "", nil, err
+			}
+			args = append(args, g.convert(code, argTipe, target.Params[i].Tipe))
+		}
+		return fmt.Sprintf("%s(%s)", goName(target.Name), strings.Join(args, ", ")), t, nil
+	}
+
+	return "", nil, unsupported(node, node.Name())
+}
+
+// element gives a pointer to an array element, a.At(i).At(j) for a[i][j].
+// structLiteral writes a struct literal as a Go composite literal. Fields it
+// leaves out keep their zero value, which for arrays and structs inside means
+// building one.
+func (g *goEmitter) structLiteral(n common.StructLiteralNode) (string, *common.Error) {
+	fields, values, err := g.prog.isiStruct(n)
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, 0, len(fields))
+	for idx, f := range fields {
+		if values[idx] == nil {
+			if !f.Tipe.isComposite() {
+				continue
+			}
+			zero, err := g.zero(f.Tipe)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, fmt.Sprintf("%s: %s", goName(f.Name), zero))
+			continue
+		}
+		value, t, err := g.storedValue(values[idx])
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", goName(f.Name), bare(g.convert(value, t, f.Tipe))))
+	}
+	return fmt.Sprintf("%s{%s}", goName(g.prog.typeOf(n, g.fn).Name), strings.Join(parts, ", ")), nil
+}
+
+// storedValue is expr for a value about to be stored, cloned when it is an
+// array or struct that stays with its variable.
+func (g *goEmitter) storedValue(node common.Expr) (string, *tipe, *common.Error) {
+	code, t, err := g.expr(node)
+	if err != nil || !g.prog.perluSalin(node, g.fn) {
+		return code, t, err
+	}
+	return fmt.Sprintf("clone(%s)", bare(code)), t, nil
+}
+
+func (g *goEmitter) element(n common.ArrayIndexNode) (string, *common.Error) {
+	var left string
+	var err *common.Error
+	if inner, ok := n.Left.(common.ArrayIndexNode); ok {
+		left, err = g.element(inner)
+	} else {
+		left, _, err = g.expr(n.Left)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	index, indexTipe, err := g.expr(n.Index)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.At(%s)", left, bare(g.convert(index, indexTipe, tipeInteger))), nil
+}
+
+func (g *goEmitter) binOp(n common.BinOpNode, t *tipe) (string, *tipe, *common.Error) {
+	op := n.Operator.Value
+	if op == "&&" || op == "||" {
+		left, err := g.cond(n.Left)
+		if err != nil {
+			return "", nil, err
+		}
+		right, err := g.cond(n.Right)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("(%s %s %s)", left, op, right), t, nil
+	}
+
+	left, leftTipe, err := g.expr(n.Left)
+	if err != nil {
+		return "", nil, err
+	}
+	right, rightTipe, err := g.expr(n.Right)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if leftTipe.Kind == tString {
+		switch op {
+		case "*":
+			return fmt.Sprintf("strings.Repeat(%s, %s)", left, g.convert(right, rightTipe, tipeInteger)), t, nil
+		case "+", "==", "!=", "<", "<=", ">", ">=":
+			if rightTipe.Kind == tString {
+				return fmt.Sprintf("(%s %s %s)", left, op, right), t, nil
+			}
+		}
+		return "", nil, unsupported(n, fmt.Sprintf("'%s' between string and non-string", op))
+	}
+
+	if (op == "==" || op == "!=") && leftTipe.isComposite() && rightTipe.isComposite() {
+		g.pakaiReflect = true
+		sama := fmt.Sprintf("reflect.DeepEqual(%s, %s)", bare(left), bare(right))
+		if op == "!=" {
+			sama = "!" + sama
+		}
+		return sama, t, nil
+	}
+
+	if !leftTipe.isNumber() || !rightTipe.isNumber() {
+		return "", nil, unsupported(n, fmt.Sprintf("'%s' on non-numeric values", op))
+	}
+
+	// Integers only stay integers when both sides are; everything else is
+	// done in float64 the way the interpreter always does.
+	operand := tipeInteger
+	if leftTipe.Kind == tReal || rightTipe.Kind == tReal || op == "/" || op == "^" {
+		operand = tipeReal
+	}
+	left = g.convert(left, leftTipe, operand)
+	right = g.convert(right, rightTipe, operand)
+
+	if op == "^" {
+		g.pakaiMath = true
+		return fmt.Sprintf("math.Pow(%s, %s)", left, right), t, nil
+	}
+
+	return fmt.Sprintf("(%s %s %s)", left, op, right), t, nil
+}
+
+func (g *goEmitter) statements(statements []common.Expr) *common.Error {
+	for _, statement := range statements {
+		g.commentsUntil(startLine(statement))
+		if err := g.statement(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *goEmitter) statement(node common.Expr) *common.Error {
+	switch n := node.(type) {
+	case nil, common.NullNode:
+		return nil
+	case *common.ParseResult:
+		return g.statement(n.Node)
+	case common.ListNode:
+		return g.statements(flatten(n))
+	case common.VarAssignNode:
+		target := g.prog.lookup(n.VarName.Value, g.fn)
+		if target != nil && target.Const {
+			err := common.TranslationError(posOf(n.Pos_Start), posOf(n.Pos_end), fmt.Sprintf("Constant variable '%s' can not be assigned!", n.VarName.Value))
+			return &err
+		}
+		value, t, err := g.storedValue(n.ValueNode)
+		if err != nil {
+			return err
+		}
+		g.line("%s = %s", goName(n.VarName.Value), bare(g.convert(value, t, target.Tipe)))
+	case common.ArrayAssignNode:
+		if g.prog.typeOf(n.ArrayAccess.Left, g.fn).Kind == tString {
+			return unsupported(n, "assigning to a character of a string")
+		}
+		target, targetTipe, err := g.expr(n.ArrayAccess)
+		if err != nil {
+			return err
+		}
+		value, t, err := g.storedValue(n.ValueNode)
+		if err != nil {
+			return err
+		}
+		g.line("%s = %s", target, bare(g.convert(value, t, targetTipe)))
+	case common.MemberAssignNode:
+		target, targetTipe, err := g.expr(n.MemberAccess)
+		if err != nil {
+			return err
+		}
+		value, t, err := g.storedValue(n.ValueNode)
+		if err != nil {
+			return err
+		}
+		g.line("%s = %s", target, bare(g.convert(value, t, targetTipe)))
+	case common.CallNode:
+		return g.call(n)
+	case common.IfNode:
+		for i, c := range n.Cases {
+			kondisi, err := g.cond(c.Kondisi)
+			if err != nil {
+				return err
+			}
+			if i == 0 {
+				g.line("if %s {", bare(kondisi))
+			} else {
+				g.indent--
+				g.line("} else if %s {", bare(kondisi))
+			}
+			g.indent++
+			if err := g.statement(c.Isi); err != nil {
+				return err
+			}
+		}
+		if n.Else_case != nil && n.Else_case.Isi != nil {
+			g.indent--
+			g.line("} else {")
+			g.indent++
+			if err := g.statement(n.Else_case.Isi); err != nil {
+				return err
+			}
+		}
+		g.indent--
+		g.line("}")
+	case common.WhileNode:
+		kondisi, err := g.cond(n.KondisiNode)
+		if err != nil {
+			return err
+		}
+		g.line("for %s {", bare(kondisi))
+		g.indent++
+		if err := g.statement(n.BodyNode); err != nil {
+			return err
+		}
+		g.indent--
+		g.line("}")
+	case common.RepeatNode:
+		g.line("for {")
+		g.indent++
+		if err := g.statement(n.BodyNode); err != nil {
+			return err
+		}
+		kondisi, err := g.cond(n.KondisiNode)
+		if err != nil {
+			return err
+		}
+		g.line("if %s {", bare(kondisi))
+		g.line("\tbreak")
+		g.line("}")
+		g.indent--
+		g.line("}")
+	case common.ForNode:
+		return g.forLoop(n)
+	case common.ForEachNode:
+		loop, err := g.prog.lowerForeach(n, g.fn)
+		if err != nil {
+			return err
+		}
+		return g.forLoop(loop)
+	case common.CaseNode:
+		pilihan, err := lowerCase(n)
+		if err != nil {
+			return err
+		}
+		return g.statement(pilihan)
+	case common.ReturnNode:
+		if g.fn == nil {
+			return unsupported(n, "'return' outside a function")
+		}
+		if n.NodeToReturn == nil || g.fn.Return == nil {
+			if g.fn.Return != nil {
+				zero, _ := g.zero(g.fn.Return)
+				g.line("return %s", zero)
+			} else {
+				g.line("return")
+			}
+			return nil
+		}
+		value, t, err := g.expr(n.NodeToReturn)
+		if err != nil {
+			return err
+		}
+		g.line("return %s", bare(g.convert(value, t, g.fn.Return)))
+	case common.BreakNode:
+		g.line("break")
+	case common.ContinueNode:
+		g.line("continue")
+	default:
+		return unsupported(node, node.Name())
+	}
+
+	return nil
+}
+
+func (g *goEmitter) forLoop(n common.ForNode) *common.Error {
+	n = countDown(n)
+	counter := g.prog.lookup(n.VarNameTok.Value, g.fn)
+	nama := goName(n.VarNameTok.Value)
+
+	start, startTipe, err := g.expr(n.StartValueNode)
+	if err != nil {
+		return err
+	}
+	end, endTipe, err := g.expr(n.EndValueNode)
+	if err != nil {
+		return err
+	}
+	start = g.convert(start, startTipe, counter.Tipe)
+	end = g.convert(end, endTipe, counter.Tipe)
+
+	k, akhir, langkah := g.prog.namaLoop(n, g.fn)
+	k, akhir, langkah = goName(k), goName(akhir), goName(langkah)
+
+	if _, ok := n.StepValueNode.(common.NullNode); ok || n.StepValueNode == nil {
+		g.line("for %s, %s := %s, %s; %s <= %s; %s++ {", k, akhir, bare(start), bare(end), k, akhir, k)
+	} else {
+		step, stepTipe, err := g.expr(n.StepValueNode)
+		if err != nil {
+			return err
+		}
+		step = g.convert(step, stepTipe, counter.Tipe)
+
+		switch {
+		case isNegativeLiteral(n.StepValueNode):
+			g.line("for %s, %s := %s, %s; %s >= %s; %s += %s {", k, akhir, bare(start), bare(end), k, akhir, k, step)
+		case isLiteral(n.StepValueNode):
+			g.line("for %s, %s := %s, %s; %s <= %s; %s += %s {", k, akhir, bare(start), bare(end), k, akhir, k, step)
+		default:
+			g.line("for %s, %s, %s := %s, %s, %s; (%s >= 0 && %s <= %s) || (%s < 0 && %s >= %s); %s += %s {", k, akhir, langkah, bare(start), bare(end), bare(step), langkah, k, akhir, langkah, k, akhir, k, langkah)
+		}
+	}
+
+	g.indent++
+	g.line("%s = %s", nama, k)
+	if err := g.statement(n.BodyNode); err != nil {
+		return err
+	}
+	g.indent--
+	g.line("}")
+
+	return nil
+}
+
+func isLiteral(node common.Expr) bool {
+	_, ok := node.(common.NumberNode)
+	return ok
+}
+
+func isNegativeLiteral(node common.Expr) bool {
+	unary, ok := node.(common.UnaryOpNode)
+	return ok && unary.Operator.Value == "-" && isLiteral(unary.Node)
+}
+
+// writeArg turns an argument of write into a Go string, formatting x:8:2 the
+// way the interpreter does.
+func (g *goEmitter) writeArg(arg common.Expr) (string, *common.Error) {
+	format, ok := arg.(common.FormatNode)
+	if !ok {
+		code, _, err := g.expr(arg)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("show(%s)", bare(code)), nil
+	}
+
+	code, t, err := g.expr(format.Value)
+	if err != nil {
+		return "", err
+	}
+	width, err := widthOf(format.Width, g.expr)
+	if err != nil {
+		return "", err
+	}
+	if format.Precision == nil {
+		return fmt.Sprintf("fmt.Sprintf(\"%%*s\", %s, show(%s))", width, bare(code)), nil
+	}
+
+	if t.Kind != tInteger && t.Kind != tReal {
+		return "", unsupported(format.Value, "writing a non-number with decimals")
+	}
+	precision, err := widthOf(format.Precision, g.expr)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("fmt.Sprintf(\"%%*.*f\", %s, %s, float64(%s))", width, precision, bare(code)), nil
+}
+
+func (g *goEmitter) call(n common.CallNode) *common.Error {
+	callee, ok := n.NodeToCall.(common.VarAccessNode)
+	if !ok {
+		return unsupported(n, "calling a non-function value")
+	}
+
+	switch {
+	case isWrite(callee.VarNameTok.Value):
+		parts := make([]string, 0, len(n.ArgNodes)+1)
+		for _, arg := range n.ArgNodes {
+			code, err := g.writeArg(arg)
+			if err != nil {
+				return err
+			}
+			parts = append(parts, code)
+		}
+		if isWriteln(callee.VarNameTok.Value) {
+			parts = append(parts, `"\n"`)
+		}
+		if len(parts) > 0 {
+			g.line("fmt.Fprint(out, %s)", strings.Join(parts, ", "))
+		}
+	case isRead(callee.VarNameTok.Value):
+		barisHabis := false
+		for _, arg := range n.ArgNodes {
+			barisHabis = false
+			switch arg.(type) {
+			case common.VarAccessNode, common.MemberAccessNode:
+				code, t, err := g.expr(arg)
+				if err != nil {
+					return err
+				}
+				if isReadln(callee.VarNameTok.Value) && t.Kind == tString {
+					g.line("%s = readLine()", code)
+					barisHabis = true
+					continue
+				}
+				g.line("scan(%q, %q, &%s)", callee.VarNameTok.Value, arg.Print(), code)
+			case common.ArrayIndexNode:
+				code, err := g.element(arg.(common.ArrayIndexNode))
+				if err != nil {
+					return err
+				}
+				if isReadln(callee.VarNameTok.Value) && g.prog.typeOf(arg, g.fn).Kind == tString {
+					g.line("*%s = readLine()", code)
+					barisHabis = true
+					continue
+				}
+				g.line("scan(%q, %q, %s)", callee.VarNameTok.Value, arg.Print(), code)
+			default:
+				return unsupported(arg, "reading into an expression")
+			}
+		}
+		if isReadln(callee.VarNameTok.Value) && !barisHabis {
+			g.line("readLine()")
+		}
+	default:
+		code, _, err := g.expr(n)
+		if err != nil {
+			return err
+		}
+		g.line("%s", code)
+	}
+
+	return nil
+}
@@ -0,0 +1,713 @@
+package translator
+
+import (
+	"dap/internal/common"
+	"dap/internal/lexer"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var pythonReserved = []string{
+	"False", "None", "True", "and", "as", "assert", "async", "await", "class", "def", "del", "elif", "else", "except",
+	"finally", "for", "from", "global", "if", "import", "in", "is", "lambda", "nonlocal", "not", "or", "pass", "raise",
+	"return", "try", "while", "with", "yield",
+	"print", "input", "int", "float", "str", "range", "len", "list", "dataclass", "field", "copy",
+	"sys", "read_token", "read_line", "float_range",
+}
+
+// pyReader reads input the way the interpreter does: read takes the next
+// whitespace-separated value, wherever it is, while readln into a string
+// takes the rest of the current line.
+const pyReader = `
+
+# The words of the current line not read yet, or None at the start of a line.
+_words = None
+
+
+def read_token(function, name):
+    global _words
+    while not _words:
+        line = sys.stdin.readline()
+        if not line:
+            sys.exit("Runtime Error: %s: no more input to read into '%s'" % (function, name))
+        _words = line.split()
+    return _words.pop(0)
+
+
+def read_line():
+    global _words
+    if _words is None:
+        line = sys.stdin.readline().rstrip("\n")
+    else:
+        line = " ".join(_words)
+    _words = None
+    return line
+`
+
+// pyRange counts the way a for loop does in the interpreter when a bound or
+// the step isn't a whole number, which range won't take.
+const pyRange = `
+
+def float_range(start, end, step):
+    k = 0
+    while True:
+        value = float("%.15g" % (start + k * step))
+        if (step > 0 and value > end) or (step < 0 and value < end):
+            return
+        yield int(value) if value.is_integer() else value
+        k += 1
+`
+
+type pythonEmitter struct {
+	penulis
+	prog         *program
+	fn           *function
+	pakaiData    bool
+	pakaiCopy    bool
+	pakaiBaca    bool
+	pakaiRentang bool
+}
+
+// ToPython translates a parsed DAP program into a readable Python 3 script,
+// keeping the original names and comments.
+func ToPython(node common.Expr, programName string, comments []lexer.Comment) (string, *common.Error) {
+	prog, err := analyse(node, programName)
+	if err != nil {
+		return "", err
+	}
+
+	py := &pythonEmitter{prog: prog, penulis: penulis{tab: "    ", komentar: "#", comments: comments}}
+	body, err := py.emitProgram()
+	if err != nil {
+		return "", err
+	}
+
+	hasil := fmt.Sprintf("# Translated from DAP program %s\n", prog.Name)
+	if py.pakaiCopy {
+		hasil += "import copy\n"
+	}
+	if py.pakaiBaca {
+		hasil += "import sys\n"
+	}
+	if py.pakaiData {
+		hasil += "from dataclasses import dataclass, field\n"
+	}
+	prelude := ""
+	if py.pakaiBaca {
+		prelude += pyReader
+	}
+	if py.pakaiRentang {
+		prelude += pyRange
+	}
+	if prelude != "" {
+		hasil += prelude
+		// Classes already start two lines down; globals need the second line.
+		if len(py.prog.Structs) == 0 {
+			hasil += "\n"
+		}
+	}
+	return hasil + body, nil
+}
+
+func pyName(name string) string {
+	if slices.Contains(pythonReserved, name) {
+		return name + "_"
+	}
+	return name
+}
+
+func (py *pythonEmitter) emitProgram() (string, *common.Error) {
+	for _, s := range py.prog.Structs {
+		py.pakaiData = true
+		py.line("")
+		py.line("")
+		py.commentsUntil(startLine(s))
+		py.line("@dataclass")
+		py.line("class %s:", pyName(s.StructName.Value))
+		py.indent++
+		for _, f := range py.prog.structs[s.StructName.Value] {
+			if f.Tipe == nil {
+				return "", unsupported(f.Node, fmt.Sprintf("type '%s'", f.Node.Print()))
+			}
+			zero, err := py.zero(f.Tipe)
+			if err != nil {
+				return "", err
+			}
+			if f.Tipe.Kind == tArray || f.Tipe.Kind == tStruct {
+				zero = fmt.Sprintf("field(default_factory=lambda: %s)", zero)
+			}
+			py.line("%s: %s = %s", pyName(f.Name), py.pyType(f.Tipe), zero)
+		}
+		py.indent--
+	}
+
+	// Aliases of structs come after the classes they name.
+	pertama := true
+	for _, alias := range py.prog.Aliases {
+		py.commentsUntil(startLine(alias))
+		if _, ok := py.prog.structs[alias.AliasName.Value]; !ok {
+			if target, ok := alias.TargetType.(common.VarAccessNode); ok {
+				if _, ok := py.prog.structs[target.VarNameTok.Value]; ok {
+					if pertama {
+						py.line("")
+						py.line("")
+						pertama = false
+					}
+					py.line("%s = %s", pyName(alias.AliasName.Value), pyName(target.VarNameTok.Value))
+				}
+			}
+		}
+	}
+
+	if len(py.prog.Structs) > 0 {
+		py.line("")
+	}
+	py.line("")
+	for _, v := range py.prog.Globals {
+		if v.Node == nil {
+			continue
+		}
+		py.commentsUntil(startLine(v.Node))
+
+		var value string
+		var err *common.Error
+		if v.Const {
+			value, _, err = py.expr(v.Value)
+		} else {
+			value, err = py.zero(v.Tipe)
+		}
+		if err != nil {
+			return "", err
+		}
+		py.line("%s = %s", pyName(v.Name), value)
+	}
+
+	for _, fn := range py.prog.Functions {
+		if err := py.emitFunction(fn); err != nil {
+			return "", err
+		}
+	}
+
+	py.line("")
+	py.line("")
+	py.line("if __name__ == \"__main__\":")
+	py.indent++
+	if err := py.block(py.prog.Main); err != nil {
+		return "", err
+	}
+	py.restComments()
+	py.indent--
+
+	return py.buf.String(), nil
+}
+
+func (py *pythonEmitter) emitFunction(fn *function) *common.Error {
+	py.fn = fn
+	defer func() { py.fn = nil }()
+
+	params := make([]string, 0, len(fn.Params))
+	for _, p := range fn.Params {
+		params = append(params, pyName(p.Name))
+	}
+
+	py.line("")
+	py.line("")
+	py.commentsUntil(startLine(fn.Node))
+	py.line("def %s(%s):", pyName(fn.Name), strings.Join(params, ", "))
+	py.indent++
+	defer func() { py.indent-- }()
+	for _, p := range fn.Params {
+		if p.Tipe.isComposite() {
+			py.pakaiCopy = true
+			py.line("%s = copy.deepcopy(%s)", pyName(p.Name), pyName(p.Name))
+		}
+	}
+
+	if fn.Node.ShouldAutoReturn {
+		value, _, err := py.expr(fn.Node.BodyNode)
+		if err != nil {
+			return err
+		}
+		py.line("return %s", bare(value))
+		return nil
+	}
+
+	return py.block(flatten(fn.Node.BodyNode))
+}
+
+func (py *pythonEmitter) pyType(t *tipe) string {
+	switch t.Kind {
+	case tInteger:
+		return "int"
+	case tReal:
+		return "float"
+	case tString:
+		return "str"
+	case tBool:
+		return "bool"
+	case tArray:
+		return fmt.Sprintf("list[%s]", py.pyType(t.Elem))
+	case tStruct:
+		return pyName(t.Name)
+	}
+
+	return "float"
+}
+
+func (py *pythonEmitter) zero(t *tipe) (string, *common.Error) {
+	switch t.Kind {
+	case tInteger:
+		return "0", nil
+	case tReal:
+		return "0.0", nil
+	case tString:
+		return `""`, nil
+	case tBool:
+		return "False", nil
+	case tArray:
+		start, _, err := py.expr(t.Start)
+		if err != nil {
+			return "", err
+		}
+		end, _, err := py.expr(t.End)
+		if err != nil {
+			return "", err
+		}
+		elem, err := py.zero(t.Elem)
+		if err != nil {
+			return "", err
+		}
+
+		size := ukuran(start, end)
+		if t.Elem.Kind == tArray || t.Elem.Kind == tStruct {
+			return fmt.Sprintf("[%s for _ in range(%s)]", elem, size), nil
+		}
+		if strings.Contains(size, " ") {
+			size = "(" + size + ")"
+		}
+		return fmt.Sprintf("[%s] * %s", elem, size), nil
+	case tStruct:
+		return pyName(t.Name) + "()", nil
+	}
+
+	return "0", nil
+}
+
+func (py *pythonEmitter) expr(node common.Expr) (string, *tipe, *common.Error) {
+	t := py.prog.typeOf(node, py.fn)
+
+	switch n := node.(type) {
+	case *common.ParseResult:
+		return py.expr(n.Node)
+	case common.NumberNode:
+		return n.Token.Value, t, nil
+	case common.StringNode:
+		return n.Token.Value, t, nil
+	case common.VarAccessNode:
+		switch n.VarNameTok.Value {
+		case "true":
+			return "True", t, nil
+		case "false":
+			return "False", t, nil
+		}
+		if py.prog.lookup(n.VarNameTok.Value, py.fn) == nil {
+			err := common.TranslationError(posOf(n.Pos_Start), posOf(n.Pos_end), fmt.Sprintf("'%s' is not defined", n.VarNameTok.Value))
+			return "", nil, &err
+		}
+		return pyName(n.VarNameTok.Value), t, nil
+	case common.UnaryOpNode:
+		code, _, err := py.expr(n.Node)
+		if err != nil {
+			return "", nil, err
+		}
+		if n.Operator.Value == "!" {
+			return fmt.Sprintf("(not %s)", code), t, nil
+		}
+		return n.Operator.Value + code, t, nil
+	case common.BinOpNode:
+		left, _, err := py.expr(n.Left)
+		if err != nil {
+			return "", nil, err
+		}
+		right, _, err := py.expr(n.Right)
+		if err != nil {
+			return "", nil, err
+		}
+
+		op := n.Operator.Value
+		switch op {
+		case "&&":
+			op = "and"
+		case "||":
+			op = "or"
+		case "^":
+			op = "**"
+		}
+		return fmt.Sprintf("(%s %s %s)", left, op, right), t, nil
+	case common.StructLiteralNode:
+		fields, values, err := py.prog.isiStruct(n)
+		if err != nil {
+			return "", nil, err
+		}
+		args := make([]string, 0, len(fields))
+		for idx, f := range fields {
+			if values[idx] == nil {
+				continue
+			}
+			value, err := py.storedValue(values[idx])
+			if err != nil {
+				return "", nil, err
+			}
+			args = append(args, fmt.Sprintf("%s=%s", pyName(f.Name), bare(value)))
+		}
+		return fmt.Sprintf("%s(%s)", pyName(t.Name), strings.Join(args, ", ")), t, nil
+	case common.ArrayIndexNode:
+		left, leftTipe, err := py.expr(n.Left)
+		if err != nil {
+			return "", nil, err
+		}
+		index, indexTipe, err := py.expr(n.Index)
+		if err != nil {
+			return "", nil, err
+		}
+		if indexTipe.Kind == tReal {
+			index = fmt.Sprintf("int(%s)", bare(index))
+		}
+		start := "0"
+		if leftTipe.Kind == tArray {
+			start, _, err = py.expr(leftTipe.Start)
+			if err != nil {
+				return "", nil, err
+			}
+		} else if leftTipe.Kind == tString {
+			start = "1"
+		}
+		return fmt.Sprintf("%s[%s]", left, geser(bare(index), start)), t, nil
+	case common.MemberAccessNode:
+		object, _, err := py.expr(n.Object)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s.%s", object, pyName(n.MemberTok.Value)), t, nil
+	case common.CallNode:
+		callee, ok := n.NodeToCall.(common.VarAccessNode)
+		if !ok || py.prog.functions[callee.VarNameTok.Value] == nil {
+			return "", nil, unsupported(n, fmt.Sprintf("call to '%s'", n.NodeToCall.Print()))
+		}
+		args := make([]string, 0, len(n.ArgNodes))
+		for _, arg := range n.ArgNodes {
+			code, _, err := py.expr(arg)
+			if err != nil {
+				return "", nil, err
+			}
+			args = append(args, bare(code))
+		}
+		return fmt.Sprintf("%s(%s)", pyName(callee.VarNameTok.Value), strings.Join(args, ", ")), t, nil
+	}
+
+	return "", nil, unsupported(node, node.Name())
+}
+
+// block writes an indented suite, which Python doesn't allow to be empty.
+func (py *pythonEmitter) block(statements []common.Expr) *common.Error {
+	panjang := py.buf.Len()
+	for _, statement := range statements {
+		py.commentsUntil(startLine(statement))
+		if err := py.statement(statement); err != nil {
+			return err
+		}
+	}
+
+	if py.buf.Len() == panjang {
+		py.line("pass")
+	}
+	return nil
+}
+
+// storedValue is expr for a value about to be stored, copied when it is an
+// array or struct that stays with its variable.
+func (py *pythonEmitter) storedValue(node common.Expr) (string, *common.Error) {
+	code, _, err := py.expr(node)
+	if err != nil || !py.prog.perluSalin(node, py.fn) {
+		return code, err
+	}
+	py.pakaiCopy = true
+	return fmt.Sprintf("copy.deepcopy(%s)", bare(code)), nil
+}
+
+func (py *pythonEmitter) statement(node common.Expr) *common.Error {
+	switch n := node.(type) {
+	case nil, common.NullNode:
+		return nil
+	case *common.ParseResult:
+		return py.statement(n.Node)
+	case common.ListNode:
+		return py.block(flatten(n))
+	case common.VarAssignNode:
+		value, err := py.storedValue(n.ValueNode)
+		if err != nil {
+			return err
+		}
+		py.line("%s = %s", pyName(n.VarName.Value), bare(value))
+	case common.ArrayAssignNode:
+		if py.prog.typeOf(n.ArrayAccess.Left, py.fn).Kind == tString {
+			return unsupported(n, "assigning to a character of a string")
+		}
+		target, _, err := py.expr(n.ArrayAccess)
+		if err != nil {
+			return err
+		}
+		value, err := py.storedValue(n.ValueNode)
+		if err != nil {
+			return err
+		}
+		py.line("%s = %s", target, bare(value))
+	case common.MemberAssignNode:
+		target, _, err := py.expr(n.MemberAccess)
+		if err != nil {
+			return err
+		}
+		value, err := py.storedValue(n.ValueNode)
+		if err != nil {
+			return err
+		}
+		py.line("%s = %s", target, bare(value))
+	case common.CallNode:
+		return py.call(n)
+	case common.IfNode:
+		for i, c := range n.Cases {
+			kondisi, _, err := py.expr(c.Kondisi)
+			if err != nil {
+				return err
+			}
+			keyword := "if"
+			if i > 0 {
+				keyword = "elif"
+			}
+			py.line("%s %s:", keyword, bare(kondisi))
+			py.indent++
+			if err := py.block(flatten(c.Isi)); err != nil {
+				return err
+			}
+			py.indent--
+		}
+		if n.Else_case != nil && n.Else_case.Isi != nil {
+			py.line("else:")
+			py.indent++
+			if err := py.block(flatten(n.Else_case.Isi)); err != nil {
+				return err
+			}
+			py.indent--
+		}
+	case common.WhileNode:
+		kondisi, _, err := py.expr(n.KondisiNode)
+		if err != nil {
+			return err
+		}
+		py.line("while %s:", bare(kondisi))
+		py.indent++
+		if err := py.block(flatten(n.BodyNode)); err != nil {
+			return err
+		}
+		py.indent--
+	case common.RepeatNode:
+		py.line("while True:")
+		py.indent++
+		if err := py.block(flatten(n.BodyNode)); err != nil {
+			return err
+		}
+		kondisi, _, err := py.expr(n.KondisiNode)
+		if err != nil {
+			return err
+		}
+		py.line("if %s:", bare(kondisi))
+		py.line("    break")
+		py.indent--
+	case common.ForNode:
+		return py.forLoop(n)
+	case common.ForEachNode:
+		loop, err := py.prog.lowerForeach(n, py.fn)
+		if err != nil {
+			return err
+		}
+		return py.forLoop(loop)
+	case common.CaseNode:
+		pilihan, err := lowerCase(n)
+		if err != nil {
+			return err
+		}
+		return py.statement(pilihan)
+	case common.ReturnNode:
+		if n.NodeToReturn == nil {
+			py.line("return")
+			return nil
+		}
+		value, _, err := py.expr(n.NodeToReturn)
+		if err != nil {
+			return err
+		}
+		py.line("return %s", bare(value))
+	case common.BreakNode:
+		py.line("break")
+	case common.ContinueNode:
+		py.line("continue")
+	default:
+		return unsupported(node, node.Name())
+	}
+
+	return nil
+}
+
+func (py *pythonEmitter) forLoop(n common.ForNode) *common.Error {
+	n = countDown(n)
+	start, startTipe, err := py.expr(n.StartValueNode)
+	if err != nil {
+		return err
+	}
+	end, endTipe, err := py.expr(n.EndValueNode)
+	if err != nil {
+		return err
+	}
+
+	step, stepTipe := "1", tipeInteger
+	if _, ok := n.StepValueNode.(common.NullNode); !ok && n.StepValueNode != nil {
+		step, stepTipe, err = py.expr(n.StepValueNode)
+		if err != nil {
+			return err
+		}
+	}
+
+	if startTipe.Kind == tReal || endTipe.Kind == tReal || stepTipe.Kind == tReal {
+		py.pakaiRentang = true
+		py.line("for %s in float_range(%s, %s, %s):", pyName(n.VarNameTok.Value), bare(start), bare(end), bare(step))
+		py.indent++
+		if err := py.block(flatten(n.BodyNode)); err != nil {
+			return err
+		}
+		py.indent--
+		return nil
+	}
+
+	rentang := ""
+	if _, ok := n.StepValueNode.(common.NullNode); ok || n.StepValueNode == nil {
+		rentang = fmt.Sprintf("%s, %s", bare(start), plus(bare(end), 1))
+		if bare(start) == "0" {
+			rentang = plus(bare(end), 1)
+		}
+	} else {
+		switch {
+		case isNegativeLiteral(n.StepValueNode):
+			rentang = fmt.Sprintf("%s, %s, %s", bare(start), plus(bare(end), -1), step)
+		case isLiteral(n.StepValueNode):
+			rentang = fmt.Sprintf("%s, %s, %s", bare(start), plus(bare(end), 1), step)
+		default:
+			rentang = fmt.Sprintf("%s, %s + (1 if %s > 0 else -1), %s", bare(start), bare(end), step, step)
+		}
+	}
+
+	py.line("for %s in range(%s):", pyName(n.VarNameTok.Value), rentang)
+	py.indent++
+	if err := py.block(flatten(n.BodyNode)); err != nil {
+		return err
+	}
+	py.indent--
+
+	return nil
+}
+
+// writeArg translates an argument of write, padding x:8:2 like the interpreter.
+func (py *pythonEmitter) writeArg(arg common.Expr) (string, *common.Error) {
+	format, ok := arg.(common.FormatNode)
+	if !ok {
+		code, _, err := py.expr(arg)
+		return bare(code), err
+	}
+
+	code, t, err := py.expr(format.Value)
+	if err != nil {
+		return "", err
+	}
+	width, err := widthOf(format.Width, py.expr)
+	if err != nil {
+		return "", err
+	}
+	if format.Precision == nil {
+		return fmt.Sprintf("str(%s).rjust(%s)", bare(code), width), nil
+	}
+
+	if t.Kind != tInteger && t.Kind != tReal {
+		return "", unsupported(format.Value, "writing a non-number with decimals")
+	}
+	precision, err := widthOf(format.Precision, py.expr)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("\"%%*.*f\" %% (%s, %s, %s)", width, precision, bare(code)), nil
+}
+
+func (py *pythonEmitter) call(n common.CallNode) *common.Error {
+	callee, ok := n.NodeToCall.(common.VarAccessNode)
+	if !ok {
+		return unsupported(n, "calling a non-function value")
+	}
+
+	switch {
+	case isWrite(callee.VarNameTok.Value):
+		parts := make([]string, 0, len(n.ArgNodes)+2)
+		for _, arg := range n.ArgNodes {
+			code, err := py.writeArg(arg)
+			if err != nil {
+				return err
+			}
+			parts = append(parts, code)
+		}
+		if len(parts) > 1 {
+			parts = append(parts, `sep=""`)
+		}
+		if !isWriteln(callee.VarNameTok.Value) {
+			parts = append(parts, `end=""`)
+		}
+		py.line("print(%s)", strings.Join(parts, ", "))
+	case isRead(callee.VarNameTok.Value):
+		py.pakaiBaca = true
+		fungsi := callee.VarNameTok.Value
+		barisHabis := false
+		for _, arg := range n.ArgNodes {
+			barisHabis = false
+			switch arg.(type) {
+			case common.VarAccessNode, common.ArrayIndexNode, common.MemberAccessNode:
+			default:
+				return unsupported(arg, "reading into an expression")
+			}
+
+			code, t, err := py.expr(arg)
+			if err != nil {
+				return err
+			}
+			token := fmt.Sprintf("read_token(%q, %q)", fungsi, arg.Print())
+			switch {
+			case t.Kind == tString && isReadln(fungsi):
+				py.line("%s = read_line()", code)
+				barisHabis = true
+			case t.Kind == tString:
+				py.line("%s = %s", code, token)
+			case t.Kind == tInteger:
+				py.line("%s = int(%s)", code, token)
+			default:
+				py.line("%s = float(%s)", code, token)
+			}
+		}
+		if isReadln(fungsi) && !barisHabis {
+			py.line("read_line()")
+		}
+	default:
+		code, _, err := py.expr(n)
+		if err != nil {
+			return err
+		}
+		py.line("%s", code)
+	}
+
+	return nil
+}
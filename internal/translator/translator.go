@@ -0,0 +1,913 @@
+package translator
+
+import (
+	"dap/internal/common"
+	"dap/internal/lexer"
+	"dap/tools"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type tipeKind int
+
+const (
+	tUnknown tipeKind = iota
+	tInteger
+	tReal
+	tString
+	tBool
+	tArray
+	tStruct
+)
+
+// tipe is the static type the translator infers for a DAP expression.
+// DAP itself is dynamically typed, so anything that can't be worked out
+// from the dictionary falls back to real, the same as the interpreter's Number.
+type tipe struct {
+	Kind  tipeKind
+	Name  string
+	Start common.Expr
+	End   common.Expr
+	Elem  *tipe
+}
+
+var (
+	tipeInteger = &tipe{Kind: tInteger}
+	tipeReal    = &tipe{Kind: tReal}
+	tipeString  = &tipe{Kind: tString}
+	tipeBool    = &tipe{Kind: tBool}
+)
+
+func (t *tipe) isNumber() bool {
+	return t.Kind == tInteger || t.Kind == tReal || t.Kind == tBool
+}
+
+// isComposite reports whether t is an array or a struct. DAP copies those
+// when they are assigned or passed to a function.
+func (t *tipe) isComposite() bool {
+	return t != nil && (t.Kind == tArray || t.Kind == tStruct)
+}
+
+type field struct {
+	Name string
+	Tipe *tipe
+	Node common.Expr
+}
+
+type variable struct {
+	Node     common.Expr
+	Name     string
+	Tipe     *tipe
+	TypeNode common.Expr
+	Const    bool
+	Value    common.Expr
+}
+
+type function struct {
+	Node       common.FuncNode
+	Name       string
+	Params     []*variable
+	Locals     []*variable
+	Return     *tipe
+	scope      map[string]*variable
+	inferring  bool
+	returnDone bool
+}
+
+// program is everything the backends need to know about a parsed DAP file:
+// the declarations from the dictionary, the functions lifted out of the
+// algorithm, and the algorithm statements themselves in order.
+type program struct {
+	Name      string
+	Aliases   []common.TypeAliasNode
+	Structs   []common.StructTypeNode
+	Globals   []*variable
+	Functions []*function
+	Main      []common.Expr
+
+	aliases   map[string]common.Expr
+	structs   map[string][]field
+	globals   map[string]*variable
+	functions map[string]*function
+	foreach   map[*tools.Position]common.ForNode
+}
+
+func analyse(node common.Expr, programName string) (*program, *common.Error) {
+	if pr, ok := node.(*common.ParseResult); ok {
+		node = pr.Node
+	}
+
+	prog := &program{
+		Name:      programName,
+		aliases:   map[string]common.Expr{},
+		structs:   map[string][]field{},
+		globals:   map[string]*variable{},
+		functions: map[string]*function{},
+		foreach:   map[*tools.Position]common.ForNode{},
+	}
+
+	for _, statement := range flatten(node) {
+		switch statement := statement.(type) {
+		case common.DictionaryNode:
+			if err := prog.declare(statement); err != nil {
+				return nil, err
+			}
+		case common.FuncNode:
+			if statement.VarNameTok == nil {
+				return nil, unsupported(statement, "anonymous functions")
+			}
+			fn := &function{Node: statement, Name: statement.VarNameTok.Value, scope: map[string]*variable{}}
+			prog.Functions = append(prog.Functions, fn)
+			prog.functions[fn.Name] = fn
+		case common.NullNode:
+		default:
+			prog.Main = append(prog.Main, statement)
+		}
+	}
+
+	for _, fn := range prog.Functions {
+		if err := prog.inferParams(fn); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := prog.collectLocals(prog.Main, nil); err != nil {
+		return nil, err
+	}
+
+	for _, fn := range prog.Functions {
+		body := []common.Expr{fn.Node.BodyNode}
+		if !fn.Node.ShouldAutoReturn {
+			body = flatten(fn.Node.BodyNode)
+		}
+		if err := prog.collectLocals(body, fn); err != nil {
+			return nil, err
+		}
+		prog.returnType(fn)
+	}
+
+	return prog, nil
+}
+
+func flatten(node common.Expr) []common.Expr {
+	switch node := node.(type) {
+	case nil:
+		return nil
+	case *common.ParseResult:
+		return flatten(node.Node)
+	case common.ListNode:
+		hasil := make([]common.Expr, 0, len(node.ElementNode))
+		for _, v := range node.ElementNode {
+			if v != nil {
+				hasil = append(hasil, v)
+			}
+		}
+		return hasil
+	}
+
+	return []common.Expr{node}
+}
+
+func unsupported(node common.Expr, what string) *common.Error {
+	err := common.TranslationError(posOf(node.GetPosStart()), posOf(node.GetPosEnd()), fmt.Sprintf("%s cannot be translated yet", what))
+	return &err
+}
+
+// perluSalin reports whether storing node has to copy an array or a struct
+// that a variable still holds. A call already gives a fresh value.
+func (prog *program) perluSalin(node common.Expr, fn *function) bool {
+	if parsed, ok := node.(*common.ParseResult); ok {
+		return prog.perluSalin(parsed.Node, fn)
+	}
+	switch node.(type) {
+	case common.CallNode, common.StructLiteralNode:
+		return false
+	}
+	return prog.typeOf(node, fn).isComposite()
+}
+
+// isiStruct pairs the fields of a struct literal's type with the values it
+// gives them, in declaration order. A field the literal leaves out has a nil
+// value.
+func (prog *program) isiStruct(n common.StructLiteralNode) ([]field, []common.Expr, *common.Error) {
+	t := prog.resolve(common.VarAccessNode{VarNameTok: n.TypeTok})
+	if t == nil || t.Kind != tStruct {
+		err := common.TranslationError(posOf(n.TypeTok.Pos_Start), posOf(n.TypeTok.Pos_End), fmt.Sprintf("'%s' is not a struct type", n.TypeTok.Value))
+		return nil, nil, &err
+	}
+	fields := prog.structs[t.Name]
+	values := make([]common.Expr, len(fields))
+
+	if n.Positional {
+		if selisih := len(n.Values) - len(fields); selisih != 0 {
+			pesan := fmt.Sprintf("%d too many values passed into '%s'", selisih, n.TypeTok.Value)
+			if selisih < 0 {
+				pesan = fmt.Sprintf("%d too few values passed into '%s'", -selisih, n.TypeTok.Value)
+			}
+			err := common.TranslationError(posOf(n.Pos_Start), posOf(n.Pos_End), pesan)
+			return nil, nil, &err
+		}
+		copy(values, n.Values)
+		return fields, values, nil
+	}
+
+	for _, given := range n.Fields {
+		idx := slices.IndexFunc(fields, func(f field) bool { return f.Name == given.VarName.Value })
+		if idx < 0 || values[idx] != nil {
+			pesan := fmt.Sprintf("Field '%s' not found in struct", given.VarName.Value)
+			if idx >= 0 {
+				pesan = fmt.Sprintf("Field '%s' is given twice", given.VarName.Value)
+			}
+			err := common.TranslationError(posOf(given.VarName.Pos_Start), posOf(given.VarName.Pos_End), pesan)
+			return nil, nil, &err
+		}
+		values[idx] = given.ValueNode
+	}
+	return fields, values, nil
+}
+
+func posOf(p *tools.Position) tools.Position {
+	if p == nil {
+		return tools.Position{}
+	}
+	return *p
+}
+
+func (prog *program) declare(dict common.DictionaryNode) *common.Error {
+	for _, v := range dict.VariableDiBuat {
+		switch v := v.(type) {
+		case common.TypeAliasNode:
+			switch v.TargetType.(type) {
+			case common.EnumTypeNode:
+				return unsupported(v.TargetType, "enumerations")
+			case common.SubrangeTypeNode:
+				return unsupported(v.TargetType, "subrange types")
+			}
+			prog.Aliases = append(prog.Aliases, v)
+			prog.aliases[v.AliasName.Value] = v.TargetType
+		case common.StructTypeNode:
+			fields := make([]field, 0, len(v.Fields))
+			for _, f := range v.Fields {
+				fields = append(fields, field{Name: f.VarName.Value, Node: f.ValueNode})
+			}
+			prog.Structs = append(prog.Structs, v)
+			prog.structs[v.StructName.Value] = fields
+			for i := range fields {
+				fields[i].Tipe = prog.resolve(fields[i].Node)
+			}
+		case common.VarAssignNode:
+			variabel := &variable{Node: v, Name: v.VarName.Value, Const: v.ApakahConst}
+			if v.ApakahConst {
+				variabel.Value = v.ValueNode
+				variabel.Tipe = prog.typeOf(v.ValueNode, nil)
+			} else {
+				variabel.TypeNode = v.ValueNode
+				variabel.Tipe = prog.resolve(v.ValueNode)
+				if variabel.Tipe == nil {
+					return unsupported(v.ValueNode, fmt.Sprintf("type '%s'", v.ValueNode.Print()))
+				}
+			}
+			prog.Globals = append(prog.Globals, variabel)
+			prog.globals[variabel.Name] = variabel
+		}
+	}
+
+	return nil
+}
+
+// resolve turns a type expression from parse_type into a tipe, following aliases.
+func (prog *program) resolve(typeNode common.Expr) *tipe {
+	switch t := typeNode.(type) {
+	case common.VarAccessNode:
+		switch t.VarNameTok.Value {
+		case "integer":
+			return tipeInteger
+		case "real":
+			return tipeReal
+		case "string", "character":
+			return tipeString
+		}
+
+		if target, ok := prog.aliases[t.VarNameTok.Value]; ok {
+			return prog.resolve(target)
+		}
+
+		if _, ok := prog.structs[t.VarNameTok.Value]; ok {
+			return &tipe{Kind: tStruct, Name: t.VarNameTok.Value}
+		}
+	case common.ArrayTypeNode:
+		elem := prog.resolve(t.OfType)
+		if elem == nil {
+			return nil
+		}
+		return &tipe{Kind: tArray, Start: t.StartNode, End: t.EndNode, Elem: elem}
+	}
+
+	return nil
+}
+
+func (prog *program) lookup(name string, fn *function) *variable {
+	if fn != nil {
+		if v, ok := fn.scope[name]; ok {
+			return v
+		}
+	}
+
+	return prog.globals[name]
+}
+
+// typeOf infers the static type of an expression. fn is the enclosing
+// function, or nil when the expression belongs to the algorithm section.
+func (prog *program) typeOf(node common.Expr, fn *function) *tipe {
+	switch n := node.(type) {
+	case *common.ParseResult:
+		return prog.typeOf(n.Node, fn)
+	case common.NumberNode:
+		if strings.Contains(n.Token.Value, ".") {
+			return tipeReal
+		}
+		return tipeInteger
+	case common.StringNode:
+		return tipeString
+	case common.VarAccessNode:
+		switch n.VarNameTok.Value {
+		case "true", "false":
+			return tipeBool
+		}
+		if v := prog.lookup(n.VarNameTok.Value, fn); v != nil && v.Tipe != nil {
+			return v.Tipe
+		}
+	case common.UnaryOpNode:
+		if n.Operator.Value == "!" {
+			return tipeBool
+		}
+		return prog.typeOf(n.Node, fn)
+	case common.BinOpNode:
+		left := prog.typeOf(n.Left, fn)
+		right := prog.typeOf(n.Right, fn)
+		switch n.Operator.Value {
+		case "==", "!=", "<", "<=", ">", ">=", "&&", "||":
+			return tipeBool
+		case "/", "^":
+			return tipeReal
+		case "+":
+			if left.Kind == tString {
+				return tipeString
+			}
+		case "*":
+			if left.Kind == tString {
+				return tipeString
+			}
+		}
+		if left.Kind == tReal || right.Kind == tReal {
+			return tipeReal
+		}
+		if left.Kind == tInteger && right.Kind == tInteger {
+			return tipeInteger
+		}
+		return tipeReal
+	case common.ArrayIndexNode:
+		if left := prog.typeOf(n.Left, fn); left.Kind == tArray {
+			return left.Elem
+		} else if left.Kind == tString {
+			return tipeString
+		}
+	case common.MemberAccessNode:
+		if object := prog.typeOf(n.Object, fn); object.Kind == tStruct {
+			for _, f := range prog.structs[object.Name] {
+				if f.Name == n.MemberTok.Value {
+					return f.Tipe
+				}
+			}
+		}
+	case common.StructLiteralNode:
+		if t := prog.resolve(common.VarAccessNode{VarNameTok: n.TypeTok}); t != nil && t.Kind == tStruct {
+			return t
+		}
+	case common.CallNode:
+		if callee, ok := n.NodeToCall.(common.VarAccessNode); ok {
+			if target := prog.functions[callee.VarNameTok.Value]; target != nil {
+				if t := prog.returnType(target); t != nil {
+					return t
+				}
+			}
+		}
+	}
+
+	return tipeReal
+}
+
+// inferParams types a function's parameters from the first call made to it,
+// since DAP parameters carry no declared type.
+func (prog *program) inferParams(fn *function) *common.Error {
+	var call *common.CallNode
+	walk(prog.Main, func(node common.Expr) {
+		if c, ok := node.(common.CallNode); ok && call == nil {
+			if callee, ok := c.NodeToCall.(common.VarAccessNode); ok && callee.VarNameTok.Value == fn.Name {
+				call = &c
+			}
+		}
+	})
+
+	for i, arg := range fn.Node.ArgNameToks {
+		if strings.HasPrefix(arg.Value, "...") {
+			return unsupported(fn.Node, "variadic parameters")
+		}
+
+		param := &variable{Name: arg.Value, Tipe: tipeReal}
+		if call != nil && i < len(call.ArgNodes) {
+			param.Tipe = prog.typeOf(call.ArgNodes[i], nil)
+		}
+		fn.Params = append(fn.Params, param)
+		fn.scope[param.Name] = param
+	}
+
+	return nil
+}
+
+func (prog *program) returnType(fn *function) *tipe {
+	if fn.returnDone || fn.inferring {
+		return fn.Return
+	}
+
+	fn.inferring = true
+	defer func() {
+		fn.inferring = false
+		fn.returnDone = true
+	}()
+
+	if fn.Node.ShouldAutoReturn {
+		fn.Return = prog.typeOf(fn.Node.BodyNode, fn)
+		return fn.Return
+	}
+
+	walk(flatten(fn.Node.BodyNode), func(node common.Expr) {
+		if r, ok := node.(common.ReturnNode); ok && r.NodeToReturn != nil && fn.Return == nil {
+			fn.Return = prog.typeOf(r.NodeToReturn, fn)
+		}
+	})
+
+	return fn.Return
+}
+
+// collectLocals finds variables that are assigned without being declared in
+// the dictionary (for-loop counters, function locals) and gives them a type
+// from their first assignment.
+func (prog *program) collectLocals(statements []common.Expr, fn *function) *common.Error {
+	var err *common.Error
+	tambah := func(name string, t *tipe) {
+		v := &variable{Name: name, Tipe: t}
+		if fn == nil {
+			if _, ok := prog.globals[name]; !ok {
+				prog.Globals = append(prog.Globals, v)
+				prog.globals[name] = v
+			}
+			return
+		}
+
+		// Assignments inside a function land in the function's own symbol
+		// table in the interpreter, so they become locals here as well.
+		if _, ok := fn.scope[name]; !ok {
+			fn.Locals = append(fn.Locals, v)
+			fn.scope[name] = v
+		}
+	}
+
+	walk(statements, func(node common.Expr) {
+		switch n := node.(type) {
+		case common.VarAssignNode:
+			tambah(n.VarName.Value, prog.typeOf(n.ValueNode, fn))
+		case common.ForNode:
+			t := tipeInteger
+			if prog.typeOf(n.StartValueNode, fn).Kind == tReal {
+				t = tipeReal
+			}
+			tambah(n.VarNameTok.Value, t)
+		case common.ForEachNode:
+			loop, e := prog.lowerForeach(n, fn)
+			if e != nil {
+				if err == nil {
+					err = e
+				}
+				return
+			}
+			tambah(loop.VarNameTok.Value, tipeInteger)
+			tambah(n.VarNameTok.Value, prog.typeOf(n.IterableNode, fn).Elem)
+		case common.FuncNode:
+			if err == nil {
+				err = unsupported(n, "nested functions")
+			}
+		case common.DerefNode:
+			if err == nil {
+				err = unsupported(n, "pointers")
+			}
+		case common.VarAccessNode:
+			if n.VarNameTok.Value == "nil" && err == nil {
+				err = unsupported(n, "pointers")
+			}
+		case common.CallNode:
+			if callee, ok := n.NodeToCall.(common.VarAccessNode); ok && isRead(callee.VarNameTok.Value) {
+				for _, arg := range n.ArgNodes {
+					if v, ok := arg.(common.VarAccessNode); ok {
+						tambah(v.VarNameTok.Value, tipeReal)
+					}
+				}
+			}
+		}
+	})
+
+	return err
+}
+
+// walk visits every statement and expression below the given statements,
+// without descending into nested function bodies.
+func walk(statements []common.Expr, visit func(common.Expr)) {
+	var jalan func(node common.Expr)
+	jalan = func(node common.Expr) {
+		if node == nil {
+			return
+		}
+
+		visit(node)
+		switch n := node.(type) {
+		case *common.ParseResult:
+			jalan(n.Node)
+		case common.ListNode:
+			for _, v := range n.ElementNode {
+				jalan(v)
+			}
+		case common.BinOpNode:
+			jalan(n.Left)
+			jalan(n.Right)
+		case common.UnaryOpNode:
+			jalan(n.Node)
+		case common.VarAssignNode:
+			jalan(n.ValueNode)
+		case common.IfNode:
+			for _, c := range n.Cases {
+				jalan(c.Kondisi)
+				jalan(c.Isi)
+			}
+			if n.Else_case != nil {
+				jalan(n.Else_case.Isi)
+			}
+		case common.ForNode:
+			jalan(n.StartValueNode)
+			jalan(n.EndValueNode)
+			jalan(n.StepValueNode)
+			jalan(n.BodyNode)
+		case common.ForEachNode:
+			jalan(n.IterableNode)
+			jalan(n.BodyNode)
+		case common.CaseNode:
+			jalan(n.SubjectNode)
+			for _, cabang := range n.Branches {
+				jalan(cabang.Isi)
+			}
+			jalan(n.Otherwise)
+		case common.WhileNode:
+			jalan(n.KondisiNode)
+			jalan(n.BodyNode)
+		case common.RepeatNode:
+			jalan(n.BodyNode)
+			jalan(n.KondisiNode)
+		case common.CallNode:
+			jalan(n.NodeToCall)
+			for _, v := range n.ArgNodes {
+				jalan(v)
+			}
+		case common.ReturnNode:
+			jalan(n.NodeToReturn)
+		case common.ArrayIndexNode:
+			jalan(n.Left)
+			jalan(n.Index)
+		case common.SliceNode:
+			jalan(n.Left)
+			jalan(n.Low)
+			jalan(n.High)
+		case common.StructLiteralNode:
+			for _, v := range n.Values {
+				jalan(v)
+			}
+			for _, field := range n.Fields {
+				jalan(field.ValueNode)
+			}
+		case common.ArrayAssignNode:
+			jalan(n.ArrayAccess)
+			jalan(n.ValueNode)
+		case common.MemberAccessNode:
+			jalan(n.Object)
+		case common.MemberAssignNode:
+			jalan(n.MemberAccess)
+			jalan(n.ValueNode)
+		case common.DerefNode:
+			jalan(n.Pointer)
+		case common.DerefAssignNode:
+			jalan(n.Deref)
+			jalan(n.ValueNode)
+		}
+	}
+
+	for _, v := range statements {
+		jalan(v)
+	}
+}
+
+// lowerForeach turns foreach over an array into the for loop the backends
+// already write: a counter runs over the bounds of the array, and the element
+// is assigned at the top of the body. Without an index in the source the
+// counter gets a name of its own, picked once per loop.
+func (prog *program) lowerForeach(n common.ForEachNode, fn *function) (common.ForNode, *common.Error) {
+	if loop, ok := prog.foreach[n.Pos_Start]; ok {
+		return loop, nil
+	}
+
+	switch n.IterableNode.(type) {
+	case common.VarAccessNode, common.MemberAccessNode, common.ArrayIndexNode:
+	default:
+		return common.ForNode{}, unsupported(n.IterableNode, "foreach over an expression")
+	}
+	t := prog.typeOf(n.IterableNode, fn)
+	if t.Kind != tArray {
+		return common.ForNode{}, unsupported(n.IterableNode, "foreach over anything but an array")
+	}
+
+	counter := lexer.Token{Kind: lexer.IDENTIFIER, Value: "i_" + n.VarNameTok.Value, Pos_Start: n.VarNameTok.Pos_Start, Pos_End: n.VarNameTok.Pos_End}
+	if n.IndexTok != nil {
+		counter = *n.IndexTok
+	} else {
+		for prog.lookup(counter.Value, fn) != nil {
+			counter.Value += "_"
+		}
+	}
+
+	elemen := common.VarAssignNode{
+		VarName: n.VarNameTok,
+		ValueNode: common.ArrayIndexNode{
+			Left:      n.IterableNode,
+			Index:     common.VarAccessNode{VarNameTok: counter, Pos_Start: counter.Pos_Start, Pos_end: counter.Pos_End},
+			Pos_Start: n.IterableNode.GetPosStart(),
+			Pos_End:   n.IterableNode.GetPosEnd(),
+		},
+		Pos_Start: n.VarNameTok.Pos_Start,
+		Pos_end:   n.VarNameTok.Pos_End,
+	}
+
+	loop := common.ForNode{
+		VarNameTok:     counter,
+		StartValueNode: t.Start,
+		EndValueNode:   t.End,
+		StepValueNode:  common.NullNode{},
+		BodyNode: common.ListNode{
+			ElementNode: []common.Expr{elemen, n.BodyNode},
+			Blok:        true,
+			Pos_Start:   n.Pos_Start,
+			Pos_End:     n.Pos_end,
+		},
+		ShouldReturnNull: true,
+		Pos_Start:        n.Pos_Start,
+		Pos_end:          n.Pos_end,
+	}
+	prog.foreach[n.Pos_Start] = loop
+
+	return loop, nil
+}
+
+// namaLoop picks the names a backend gives the counter, end and step of a for
+// loop. The interpreter works the end and step out once and counts on its
+// own, so changing them or the variable in the body doesn't change how often
+// it runs, and the variable keeps its last value after the loop.
+func (prog *program) namaLoop(n common.ForNode, fn *function) (counter, end, step string) {
+	pilih := func(awalan string) string {
+		nama := awalan + "_" + n.VarNameTok.Value
+		for prog.lookup(nama, fn) != nil {
+			nama += "_"
+		}
+		return nama
+	}
+	return pilih("k"), pilih("end"), pilih("step")
+}
+
+// countDown gives a downto loop as the for loop with a negative step the
+// backends already write.
+func countDown(n common.ForNode) common.ForNode {
+	if !n.Downto {
+		return n
+	}
+
+	step := n.StepValueNode
+	if _, ok := step.(common.NullNode); ok || step == nil {
+		step = common.NumberNode{
+			Token:     lexer.Token{Kind: lexer.NUMBER, Value: "1", Pos_Start: n.Pos_Start, Pos_End: n.Pos_Start},
+			Pos_Start: n.Pos_Start,
+			Pos_End:   n.Pos_Start,
+		}
+	}
+	n.StepValueNode = common.UnaryOpNode{
+		Operator:  lexer.Token{Kind: lexer.DASH, Value: "-", Pos_Start: step.GetPosStart(), Pos_End: step.GetPosStart()},
+		Node:      step,
+		Pos_Start: step.GetPosStart(),
+		Pos_End:   step.GetPosEnd(),
+	}
+	n.Downto = false
+	return n
+}
+
+// lowerCase turns case into the if/elif chain the backends already write,
+// comparing the subject against every label. The subject is then evaluated
+// once per comparison, so one that calls a function is not translated.
+func lowerCase(n common.CaseNode) (common.IfNode, *common.Error) {
+	var err *common.Error
+	walk([]common.Expr{n.SubjectNode}, func(node common.Expr) {
+		if _, ok := node.(common.CallNode); ok && err == nil {
+			err = unsupported(node, "case on a function call")
+		}
+	})
+	if err != nil {
+		return common.IfNode{}, err
+	}
+
+	banding := func(kind lexer.TokenKind, op string, left, right common.Expr) common.Expr {
+		return common.BinOpNode{
+			Left:      left,
+			Operator:  lexer.Token{Kind: kind, Value: op, Pos_Start: right.GetPosStart(), Pos_End: right.GetPosEnd()},
+			Right:     right,
+			Pos_Start: right.GetPosStart(),
+			Pos_End:   right.GetPosEnd(),
+		}
+	}
+
+	hasil := common.IfNode{Pos_Start: n.Pos_Start, Pos_end: n.Pos_End}
+	for _, cabang := range n.Branches {
+		var kondisi common.Expr
+		for _, label := range cabang.Labels {
+			cocok := banding(lexer.EQUALS, "==", n.SubjectNode, label.Low)
+			if label.High != nil {
+				cocok = banding(lexer.AND, "&&",
+					banding(lexer.GREATER_EQUALS, ">=", n.SubjectNode, label.Low),
+					banding(lexer.LESS_EQUALS, "<=", n.SubjectNode, label.High))
+			}
+			if kondisi == nil {
+				kondisi = cocok
+			} else {
+				kondisi = banding(lexer.OR, "||", kondisi, cocok)
+			}
+		}
+		hasil.Cases = append(hasil.Cases, common.IfCase{ElseCase: common.ElseCase{Isi: cabang.Isi}, Kondisi: kondisi})
+	}
+	if n.Otherwise != nil {
+		hasil.Else_case = &common.ElseCase{Isi: n.Otherwise}
+	}
+
+	return hasil, nil
+}
+
+func isRead(name string) bool {
+	switch tools.SemuaBuiltInFunction[name] {
+	case "Input", "ReadLn":
+		return true
+	}
+	return false
+}
+
+// isReadln tells readln, which skips the rest of the line afterwards and reads
+// a string as the whole rest of the line, from read.
+func isReadln(name string) bool {
+	return tools.SemuaBuiltInFunction[name] == "ReadLn"
+}
+
+func isWrite(name string) bool {
+	return tools.ApakahWrite(name)
+}
+
+// isWriteln tells writeln (and print), which end the line, from write.
+func isWriteln(name string) bool {
+	return tools.SemuaBuiltInFunction[name] == "Print"
+}
+
+// widthOf translates the width or precision of a write(x:8:2) argument,
+// which has to be an integer.
+func widthOf(node common.Expr, expr func(common.Expr) (string, *tipe, *common.Error)) (string, *common.Error) {
+	code, t, err := expr(node)
+	if err != nil {
+		return "", err
+	}
+	if t.Kind != tInteger {
+		return "", unsupported(node, "a field width that isn't an integer")
+	}
+	return bare(code), nil
+}
+
+// penulis collects the generated lines of one backend, indenting them and
+// carrying the original `//` comments over in front of the code they preceded.
+type penulis struct {
+	buf      strings.Builder
+	indent   int
+	tab      string
+	komentar string
+	comments []lexer.Comment
+}
+
+func (w *penulis) line(format string, args ...any) {
+	w.buf.WriteString(strings.Repeat(w.tab, w.indent))
+	w.buf.WriteString(fmt.Sprintf(format, args...))
+	w.buf.WriteString("\n")
+}
+
+// commentsUntil writes every pending comment that appears on or before line ln.
+func (w *penulis) commentsUntil(ln int) {
+	for len(w.comments) > 0 && w.comments[0].Pos.Ln <= ln {
+		w.line("%s %s", w.komentar, w.comments[0].Text)
+		w.comments = w.comments[1:]
+	}
+}
+
+func (w *penulis) restComments() {
+	for _, c := range w.comments {
+		w.line("%s %s", w.komentar, c.Text)
+	}
+	w.comments = nil
+}
+
+// startLine finds the source line a node begins on. Binary operators store
+// the operator's position and if-nodes their first body's, so those are
+// resolved through their leftmost child instead.
+func startLine(node common.Expr) int {
+	switch n := node.(type) {
+	case nil:
+		return -1
+	case *common.ParseResult:
+		return startLine(n.Node)
+	case common.BinOpNode:
+		return startLine(n.Left)
+	case common.IfNode:
+		return startLine(n.Cases[0].Kondisi)
+	case common.WhileNode:
+		return startLine(n.KondisiNode)
+	case common.CallNode:
+		return startLine(n.NodeToCall)
+	case common.ArrayAssignNode:
+		return startLine(n.ArrayAccess.Left)
+	case common.MemberAssignNode:
+		return startLine(n.MemberAccess.Object)
+	}
+
+	if pos := node.GetPosStart(); pos != nil {
+		return pos.Ln
+	}
+	return -1
+}
+
+// geser shifts an index so a DAP array[start..end] can live in a 0-based
+// list, folding the arithmetic away when both sides are literals.
+func geser(index string, start string) string {
+	if start == "0" {
+		return index
+	}
+
+	i, errIndex := strconv.Atoi(index)
+	s, errStart := strconv.Atoi(start)
+	if errIndex == nil && errStart == nil {
+		return strconv.Itoa(i - s)
+	}
+
+	if strings.ContainsAny(start, " +-") {
+		start = "(" + start + ")"
+	}
+	return index + " - " + start
+}
+
+// plus adds a constant to generated code, folding it into literals.
+func plus(code string, delta int) string {
+	if n, err := strconv.Atoi(code); err == nil {
+		return strconv.Itoa(n + delta)
+	}
+
+	if delta < 0 {
+		return fmt.Sprintf("%s - %d", code, -delta)
+	}
+	return fmt.Sprintf("%s + %d", code, delta)
+}
+
+// ukuran is the element count of array[start..end], as target-language code.
+func ukuran(start string, end string) string {
+	s, errStart := strconv.Atoi(start)
+	e, errEnd := strconv.Atoi(end)
+	if errStart == nil && errEnd == nil {
+		return strconv.Itoa(e - s + 1)
+	}
+
+	switch start {
+	case "1":
+		return end
+	case "0":
+		return end + " + 1"
+	}
+
+	if strings.ContainsAny(start, " +-") {
+		start = "(" + start + ")"
+	}
+	return end + " - " + start + " + 1"
+}
@@ -1,5 +1,11 @@
 package tools
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type Position struct {
 	Idx  int
 	Ln   int
@@ -44,16 +50,140 @@ func GetComparison(hasil bool) int {
 }
 
 var SemuaBuiltInFunction map[string]string = map[string]string{
-	"print": "Print",
-	"PRINT": "Print",
-	"write": "Print",
-	"WRITE": "Print",
-	"read":  "Input",
-	"READ":  "Input",
-	"input": "Input",
-	"INPUT": "Input",
+	"print":   "Print",
+	"PRINT":   "Print",
+	"writeln": "Print",
+	"WRITELN": "Print",
+	"write":   "Write",
+	"WRITE":   "Write",
+	"read":    "Input",
+	"READ":    "Input",
+	"input":   "Input",
+	"INPUT":   "Input",
+	"readln":  "ReadLn",
+	"READLN":  "ReadLn",
+
+	"length":    "Length",
+	"substring": "Substring",
+	"substr":    "Substring",
+	"upper":     "Upper",
+	"lower":     "Lower",
+	"trim":      "Trim",
+	"concat":    "Concat",
+	"find":      "Find",
+	"replace":   "Replace",
+	"split":     "Split",
+	"ord":       "Ord",
+	"chr":       "Chr",
+	"succ":      "Succ",
+	"pred":      "Pred",
+	"str":       "PrintRet",
+	"tostring":  "PrintRet",
+	"toint":     "ToInt",
+	"toreal":    "ToReal",
+	"format":    "Format",
+	"eof":       "Eof",
+	"assign":    "Assign",
+	"open":      "Open",
+	"close":     "Close",
+
+	"isinteger": "IsInteger",
+	"isreal":    "IsReal",
+	"isstring":  "IsString",
+	"typeof":    "TypeOf",
+	"append":    "Append",
+	"insert":    "Insert",
+	"remove":    "Remove",
+	"new":       "New",
+	"dispose":   "Dispose",
+
+	"abs":       "Abs",
+	"sqrt":      "Sqrt",
+	"floor":     "Floor",
+	"ceil":      "Ceil",
+	"round":     "Round",
+	"trunc":     "Trunc",
+	"min":       "Min",
+	"max":       "Max",
+	"pow":       "Pow",
+	"sin":       "Sin",
+	"cos":       "Cos",
+	"tan":       "Tan",
+	"log":       "Log",
+	"exp":       "Exp",
+	"random":    "Random",
+	"randomize": "Randomize",
 }
 
 func ApakahBuiltinFunction(s string) bool {
 	return SemuaBuiltInFunction[s] != ""
 }
+
+// ApakahBuiltinTanpaKurung reports the built-ins that can be called like a
+// statement without parentheses (write x, read n). The others need them, so
+// their names stay usable as variables.
+func ApakahBuiltinTanpaKurung(s string) bool {
+	switch SemuaBuiltInFunction[s] {
+	case "Print", "Write", "Input", "ReadLn":
+		return true
+	}
+	return false
+}
+
+// ApakahWrite reports the output built-ins, whose arguments may carry a field
+// width: write(x:8:2).
+func ApakahWrite(s string) bool {
+	switch SemuaBuiltInFunction[s] {
+	case "Print", "Write":
+		return true
+	}
+	return false
+}
+
+// UnescapeString decodes the escape sequences of a string literal written
+// without its quotes: \n, \t, \r, \", \\ and \uXXXX.
+func UnescapeString(raw string) (string, error) {
+	if !strings.Contains(raw, `\`) {
+		return raw, nil
+	}
+
+	var hasil strings.Builder
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' {
+			hasil.WriteByte(raw[i])
+			continue
+		}
+
+		if i+1 >= len(raw) {
+			return "", fmt.Errorf("String ends with a lone '\\'")
+		}
+
+		i++
+		switch raw[i] {
+		case 'n':
+			hasil.WriteByte('\n')
+		case 't':
+			hasil.WriteByte('\t')
+		case 'r':
+			hasil.WriteByte('\r')
+		case '"':
+			hasil.WriteByte('"')
+		case '\\':
+			hasil.WriteByte('\\')
+		case 'u':
+			if i+4 >= len(raw) {
+				return "", fmt.Errorf("Escape '\\u' needs 4 hex digits")
+			}
+			code, err := strconv.ParseUint(raw[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("Escape '\\u%s' needs 4 hex digits", raw[i+1:i+5])
+			}
+			hasil.WriteRune(rune(code))
+			i += 4
+		default:
+			return "", fmt.Errorf("Unknown escape sequence '\\%c'", raw[i])
+		}
+	}
+
+	return hasil.String(), nil
+}